@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"fmt"
 	"time"
 
 	"code.cloudfoundry.org/cfdev/errors"
@@ -143,3 +144,57 @@ func (b *Bosh) GetVMProgress(start time.Time, deploymentName string, isErrand bo
 
 	return VMProgress{State: Deploying, Total: total, Done: numDone, Duration: time.Now().Sub(start)}
 }
+
+type VMStatus struct {
+	Instance     string   `json:"instance"`
+	ID           string   `json:"id"`
+	ProcessState string   `json:"process_state"`
+	IPs          []string `json:"ips"`
+}
+
+type DeploymentStatus struct {
+	Name string     `json:"name"`
+	VMs  []VMStatus `json:"vms"`
+}
+
+func (b *Bosh) Deployments() ([]DeploymentStatus, error) {
+	deps, err := b.dir.Deployments()
+	if err != nil {
+		return nil, errors.SafeWrap(err, "failed to list deployments")
+	}
+
+	statuses := make([]DeploymentStatus, 0, len(deps))
+	for _, dep := range deps {
+		vmInfos, err := dep.VMInfos()
+		if err != nil {
+			return nil, errors.SafeWrap(err, "failed to list vms of "+dep.Name())
+		}
+
+		status := DeploymentStatus{Name: dep.Name(), VMs: []VMStatus{}}
+		for _, v := range vmInfos {
+			instance := v.JobName
+			if v.Index != nil {
+				instance = fmt.Sprintf("%s/%d", v.JobName, *v.Index)
+			}
+			status.VMs = append(status.VMs, VMStatus{
+				Instance:     instance,
+				ID:           v.ID,
+				ProcessState: v.ProcessState,
+				IPs:          v.IPs,
+			})
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (b *Bosh) DeleteDeployment(name string) error {
+	dep, err := b.dir.FindDeployment(name)
+	if err != nil {
+		return errors.SafeWrap(err, "failed to find deployment "+name)
+	}
+	if err := dep.Delete(false); err != nil {
+		return errors.SafeWrap(err, "failed to delete deployment "+name)
+	}
+	return nil
+}
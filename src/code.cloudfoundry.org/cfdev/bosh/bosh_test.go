@@ -77,4 +77,46 @@ var _ = Describe("Bosh", func() {
 			}).Should(Equal([]int{0, 3, 1}))
 		})
 	})
+
+	Describe("Deployments", func() {
+		It("returns the vm states of every deployment", func() {
+			index := 0
+			mockDir.EXPECT().Deployments().Return([]boshdir.Deployment{mockDep}, nil)
+			mockDep.EXPECT().Name().Return("cf").AnyTimes()
+			mockDep.EXPECT().VMInfos().Return([]boshdir.VMInfo{
+				{JobName: "router", Index: &index, ID: "some-id", ProcessState: "running", IPs: []string{"10.144.0.34"}},
+				{JobName: "api", ProcessState: "failing"},
+			}, nil)
+
+			Expect(subject.Deployments()).To(Equal([]bosh.DeploymentStatus{{
+				Name: "cf",
+				VMs: []bosh.VMStatus{
+					{Instance: "router/0", ID: "some-id", ProcessState: "running", IPs: []string{"10.144.0.34"}},
+					{Instance: "api", ProcessState: "failing"},
+				},
+			}}))
+		})
+
+		It("returns an error when the director is unreachable", func() {
+			mockDir.EXPECT().Deployments().Return(nil, errors.New("connection refused"))
+
+			_, err := subject.Deployments()
+			Expect(err).To(MatchError(ContainSubstring("connection refused")))
+		})
+	})
+
+	Describe("DeleteDeployment", func() {
+		It("deletes the named deployment", func() {
+			mockDir.EXPECT().FindDeployment("cf-mysql").Return(mockDep, nil)
+			mockDep.EXPECT().Delete(false)
+
+			Expect(subject.DeleteDeployment("cf-mysql")).To(Succeed())
+		})
+
+		It("returns an error when the deployment cannot be found", func() {
+			mockDir.EXPECT().FindDeployment("cf-mysql").Return(nil, errors.New("not found"))
+
+			Expect(subject.DeleteDeployment("cf-mysql")).To(MatchError(ContainSubstring("not found")))
+		})
+	})
 })
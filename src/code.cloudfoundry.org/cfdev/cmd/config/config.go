@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	cfdevconfig "code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/iso"
+	"github.com/spf13/cobra"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/config UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+//go:generate mockgen -package mocks -destination mocks/isoreader.go code.cloudfoundry.org/cfdev/cmd/config IsoReader
+type IsoReader interface {
+	Read(isoPath string) (iso.Metadata, error)
+}
+
+type Config struct {
+	UI         UI
+	Config     cfdevconfig.Config
+	IsoReader  IsoReader
+	ProjectDir string
+}
+
+func (c *Config) Cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "config",
+		Short: "Show the effective start settings and where each one came from",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := c.Execute(); err != nil {
+				return errors.SafeWrap(err, "cf dev config")
+			}
+			return nil
+		},
+	}
+}
+
+func (c *Config) Execute() error {
+	projectDir := c.ProjectDir
+	if projectDir == "" {
+		var err error
+		if projectDir, err = os.Getwd(); err != nil {
+			return errors.SafeWrap(err, "determining the project directory")
+		}
+	}
+
+	layers, err := cfdevconfig.StartLayers(c.Config.CFDevHome, projectDir)
+	if err != nil {
+		return err
+	}
+	settings := cfdevconfig.ResolveStartConfig(layers...)
+
+	depsIsoPath := settings.DepsFile
+	if depsIsoPath == "" {
+		depsIsoPath = filepath.Join(c.Config.CacheDir, "cf-deps.iso")
+	}
+	if _, err := os.Stat(depsIsoPath); err == nil {
+		if metadata, err := c.IsoReader.Read(depsIsoPath); err == nil {
+			settings = cfdevconfig.ResolveStartConfig(append(layers, cfdevconfig.Layer{
+				Source: cfdevconfig.SourceISO,
+				Values: cfdevconfig.StartFile{Memory: metadata.DefaultMemory},
+			})...)
+		}
+	}
+
+	for _, key := range cfdevconfig.StartConfigKeys {
+		c.UI.Say("%-19s %-24s %s", key+":", settings.Value(key), settings.Sources[key])
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/cfdev/cmd/config"
+	"code.cloudfoundry.org/cfdev/cmd/config/mocks"
+	cfdevconfig "code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/iso"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("config", func() {
+	var (
+		mockController *gomock.Controller
+		mockUI         *mocks.MockUI
+		mockIsoReader  *mocks.MockIsoReader
+		cmd            *config.Config
+		homeDir        string
+		projectDir     string
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockController)
+		mockIsoReader = mocks.NewMockIsoReader(mockController)
+
+		var err error
+		homeDir, err = ioutil.TempDir("", "cfdev-home")
+		Expect(err).NotTo(HaveOccurred())
+		projectDir, err = ioutil.TempDir("", "cfdev-project")
+		Expect(err).NotTo(HaveOccurred())
+
+		cmd = &config.Config{
+			UI: mockUI,
+			Config: cfdevconfig.Config{
+				CFDevHome: homeDir,
+				CacheDir:  filepath.Join(homeDir, "cache"),
+			},
+			IsoReader:  mockIsoReader,
+			ProjectDir: projectDir,
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+		os.RemoveAll(homeDir)
+		os.RemoveAll(projectDir)
+	})
+
+	It("reports every value with the source it came from", func() {
+		Expect(ioutil.WriteFile(filepath.Join(homeDir, "config.yml"), []byte("cpus: 6\nmemory: 8192\n"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(projectDir, "cfdev.yml"), []byte("cpus: 2\nservices: [mysql]\n"), 0644)).To(Succeed())
+
+		gomock.InOrder(
+			mockUI.EXPECT().Say("%-19s %-24s %s", "cpus:", "2", "project file ("+filepath.Join(projectDir, "cfdev.yml")+")"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "memory:", "8192", "user file ("+filepath.Join(homeDir, "config.yml")+")"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "registries:", "", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "deps_file:", "", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "services:", "mysql", "project file ("+filepath.Join(projectDir, "cfdev.yml")+")"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "system_domain:", "dev.cfdev.sh", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "bosh_director_ip:", "10.245.0.2", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "router_ip:", "10.144.0.34", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "host_ip:", cfdevconfig.DefaultHostIP, "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "container_network:", "10.246.0.0/16", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "registry_settings:", "", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "registry_mirror:", "", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "ca_certs:", "", "default"),
+			mockUI.EXPECT().Say("%-19s %-24s %s", "host_ca_certs:", "false", "default"),
+		)
+
+		Expect(cmd.Execute()).To(Succeed())
+	})
+
+	Context("when the deps iso is in the cache", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(homeDir, "cache"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(homeDir, "cache", "cf-deps.iso"), []byte{}, 0644)).To(Succeed())
+		})
+
+		It("falls back to the iso metadata for memory", func() {
+			mockIsoReader.EXPECT().Read(filepath.Join(homeDir, "cache", "cf-deps.iso")).Return(iso.Metadata{DefaultMemory: 6666}, nil)
+			mockUI.EXPECT().Say("%-19s %-24s %s", "memory:", "6666", "iso metadata")
+			mockUI.EXPECT().Say(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+			Expect(cmd.Execute()).To(Succeed())
+		})
+	})
+})
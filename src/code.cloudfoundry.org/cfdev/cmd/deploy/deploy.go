@@ -0,0 +1,168 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/provision"
+	"github.com/spf13/cobra"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/deploy UI
+type UI interface {
+	Say(message string, args ...interface{})
+	Writer() io.Writer
+}
+
+//go:generate mockgen -package mocks -destination mocks/provision.go code.cloudfoundry.org/cfdev/cmd/deploy Provisioner
+type Provisioner interface {
+	DeployManifest(name, manifest string, releases []string) error
+	ReportProgress(provision.UI, string)
+	DeleteDeployment(name string) error
+	Deployments() ([]bosh.DeploymentStatus, error)
+	GetServices() ([]provision.Service, string, error)
+}
+
+type Args struct {
+	Name     string
+	Manifest string
+	Releases []string
+	Delete   bool
+}
+
+type Deploy struct {
+	UI          UI
+	Provisioner Provisioner
+	StateDir    string
+}
+
+func (d *Deploy) Cmd() *cobra.Command {
+	args := Args{}
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy a BOSH manifest and its releases next to CF",
+		Example: `  cf dev deploy --manifest manifest.yml --release my-release.tgz
+  cf dev deploy --delete my-deployment`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, names []string) error {
+			if len(names) > 0 {
+				args.Name = names[0]
+			}
+			if err := d.Execute(args); err != nil {
+				return errors.SafeWrap(err, "cf dev deploy")
+			}
+			return nil
+		},
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVarP(&args.Manifest, "manifest", "m", "", "path to the BOSH manifest to deploy")
+	pf.StringArrayVarP(&args.Releases, "release", "r", nil, "path to a release tarball to upload before deploying, can be repeated")
+	pf.BoolVar(&args.Delete, "delete", false, "delete a deployment added with cf dev deploy, named as argument or by its --manifest")
+	return cmd
+}
+
+func (d *Deploy) Execute(args Args) error {
+	if args.Delete {
+		return d.delete(args)
+	}
+
+	if args.Name != "" {
+		return fmt.Errorf("the deployment is named by its manifest, pass the manifest with --manifest")
+	}
+	if args.Manifest == "" {
+		return fmt.Errorf("--manifest is required")
+	}
+	for _, release := range args.Releases {
+		if _, err := os.Stat(release); err != nil {
+			return fmt.Errorf("release not found: %s", release)
+		}
+	}
+
+	name, err := provision.ManifestName(args.Manifest)
+	if err != nil {
+		return err
+	}
+	if name == provision.CFDeployment {
+		return fmt.Errorf("the deployment name '%s' is used by CF Dev", name)
+	}
+	if err := d.checkName(name); err != nil {
+		return err
+	}
+
+	if err := provision.RecordCustomDeployment(d.StateDir, name, true); err != nil {
+		return errors.SafeWrap(err, "failed to record the deployment")
+	}
+
+	d.UI.Say("Deploying %s...", name)
+	d.Provisioner.ReportProgress(d.UI, name)
+	if err := d.Provisioner.DeployManifest(name, args.Manifest, args.Releases); err != nil {
+		return errors.SafeWrap(err, fmt.Sprintf("Failed to deploy %s", name))
+	}
+	return nil
+}
+
+// checkName keeps cf dev deploy away from the deployments of the packaged
+// services and the ones added with the bosh cli, which --delete would
+// otherwise be allowed to delete
+func (d *Deploy) checkName(name string) error {
+	services, _, err := d.Provisioner.GetServices()
+	if err != nil {
+		return errors.SafeWrap(err, "failed to read the services")
+	}
+	for _, service := range services {
+		if service.Deployment == name {
+			return fmt.Errorf("the deployment name '%s' is used by the %s service", name, service.Name)
+		}
+	}
+
+	if isCustom(d.StateDir, name) {
+		return nil
+	}
+	deployments, err := d.Provisioner.Deployments()
+	if err != nil {
+		return errors.SafeWrap(err, "failed to list the deployments")
+	}
+	for _, deployment := range deployments {
+		if deployment.Name == name {
+			return fmt.Errorf("a deployment named '%s' exists and was not deployed with cf dev deploy", name)
+		}
+	}
+	return nil
+}
+
+func (d *Deploy) delete(args Args) error {
+	name := args.Name
+	if name == "" && args.Manifest != "" {
+		var err error
+		if name, err = provision.ManifestName(args.Manifest); err != nil {
+			return err
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("name the deployment to delete or pass its --manifest")
+	}
+
+	if !isCustom(d.StateDir, name) {
+		return fmt.Errorf("%s was not deployed with cf dev deploy", name)
+	}
+
+	d.UI.Say("Deleting %s...", name)
+	if err := d.Provisioner.DeleteDeployment(name); err != nil {
+		return err
+	}
+	d.UI.Say("  Done")
+	return provision.RecordCustomDeployment(d.StateDir, name, false)
+}
+
+func isCustom(stateDir, name string) bool {
+	for _, n := range provision.CustomDeployments(stateDir) {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
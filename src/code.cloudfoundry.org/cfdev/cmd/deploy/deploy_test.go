@@ -0,0 +1,166 @@
+package deploy_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/cmd/deploy"
+	"code.cloudfoundry.org/cfdev/cmd/deploy/mocks"
+	"code.cloudfoundry.org/cfdev/provision"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("deploy", func() {
+	var (
+		mockController  *gomock.Controller
+		mockUI          *mocks.MockUI
+		mockProvisioner *mocks.MockProvisioner
+		tmpDir          string
+		stateDir        string
+		manifest        string
+		release         string
+		deployCmd       *deploy.Deploy
+		deploymentsCmd  *deploy.Deployments
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockController)
+		mockProvisioner = mocks.NewMockProvisioner(mockController)
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cfdev-deploy")
+		Expect(err).NotTo(HaveOccurred())
+		stateDir = filepath.Join(tmpDir, "state")
+		manifest = filepath.Join(tmpDir, "manifest.yml")
+		Expect(ioutil.WriteFile(manifest, []byte("name: my-deployment\n"), 0644)).To(Succeed())
+		release = filepath.Join(tmpDir, "my-release.tgz")
+		Expect(ioutil.WriteFile(release, []byte("release"), 0644)).To(Succeed())
+
+		deployCmd = &deploy.Deploy{UI: mockUI, Provisioner: mockProvisioner, StateDir: stateDir}
+		deploymentsCmd = &deploy.Deployments{UI: mockUI, Provisioner: mockProvisioner, StateDir: stateDir}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("deploying a manifest", func() {
+		It("deploys the manifest with its releases and records the deployment", func() {
+			gomock.InOrder(
+				mockProvisioner.EXPECT().GetServices().Return([]provision.Service{{Name: "mysql", Deployment: "cf-mysql"}}, "", nil),
+				mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{{Name: "cf"}}, nil),
+				mockUI.EXPECT().Say("Deploying %s...", "my-deployment"),
+				mockProvisioner.EXPECT().ReportProgress(mockUI, "my-deployment"),
+				mockProvisioner.EXPECT().DeployManifest("my-deployment", manifest, []string{release}),
+			)
+
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest, Releases: []string{release}})).To(Succeed())
+			Expect(provision.CustomDeployments(stateDir)).To(Equal([]string{"my-deployment"}))
+		})
+
+		It("returns the error of the deploy", func() {
+			mockProvisioner.EXPECT().GetServices()
+			mockProvisioner.EXPECT().Deployments()
+			mockUI.EXPECT().Say("Deploying %s...", "my-deployment")
+			mockProvisioner.EXPECT().ReportProgress(mockUI, "my-deployment")
+			mockProvisioner.EXPECT().DeployManifest("my-deployment", manifest, nil).Return(errors.New("some-error"))
+
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest})).To(MatchError(ContainSubstring("Failed to deploy my-deployment")))
+		})
+
+		It("requires a manifest", func() {
+			Expect(deployCmd.Execute(deploy.Args{})).To(MatchError("--manifest is required"))
+		})
+
+		It("rejects missing releases", func() {
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest, Releases: []string{"/no/such/release.tgz"}})).To(MatchError(
+				"release not found: /no/such/release.tgz",
+			))
+		})
+
+		It("does not replace the cf deployment", func() {
+			Expect(ioutil.WriteFile(manifest, []byte("name: cf\n"), 0644)).To(Succeed())
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest})).To(MatchError("the deployment name 'cf' is used by CF Dev"))
+		})
+
+		It("does not record the deployment of a packaged service", func() {
+			Expect(ioutil.WriteFile(manifest, []byte("name: cf-mysql\n"), 0644)).To(Succeed())
+			mockProvisioner.EXPECT().GetServices().Return([]provision.Service{{Name: "mysql", Deployment: "cf-mysql"}}, "", nil)
+
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest})).To(MatchError("the deployment name 'cf-mysql' is used by the mysql service"))
+			Expect(provision.CustomDeployments(stateDir)).To(BeEmpty())
+		})
+
+		It("does not record a deployment it did not create", func() {
+			mockProvisioner.EXPECT().GetServices()
+			mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{{Name: "my-deployment"}}, nil)
+
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest})).To(MatchError(
+				"a deployment named 'my-deployment' exists and was not deployed with cf dev deploy",
+			))
+			Expect(provision.CustomDeployments(stateDir)).To(BeEmpty())
+		})
+
+		It("redeploys a deployment it created", func() {
+			Expect(provision.RecordCustomDeployment(stateDir, "my-deployment", true)).To(Succeed())
+			mockProvisioner.EXPECT().GetServices()
+			mockUI.EXPECT().Say("Deploying %s...", "my-deployment")
+			mockProvisioner.EXPECT().ReportProgress(mockUI, "my-deployment")
+			mockProvisioner.EXPECT().DeployManifest("my-deployment", manifest, nil)
+
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest})).To(Succeed())
+		})
+	})
+
+	Describe("deleting a deployment", func() {
+		BeforeEach(func() {
+			Expect(provision.RecordCustomDeployment(stateDir, "my-deployment", true)).To(Succeed())
+		})
+
+		It("deletes the deployment named by the manifest", func() {
+			gomock.InOrder(
+				mockUI.EXPECT().Say("Deleting %s...", "my-deployment"),
+				mockProvisioner.EXPECT().DeleteDeployment("my-deployment"),
+				mockUI.EXPECT().Say("  Done"),
+			)
+
+			Expect(deployCmd.Execute(deploy.Args{Manifest: manifest, Delete: true})).To(Succeed())
+			Expect(provision.CustomDeployments(stateDir)).To(BeEmpty())
+		})
+
+		It("only deletes deployments added with cf dev deploy", func() {
+			Expect(deployCmd.Execute(deploy.Args{Name: "cf", Delete: true})).To(MatchError("cf was not deployed with cf dev deploy"))
+		})
+	})
+
+	Describe("listing the deployments", func() {
+		It("lists the recorded deployments with their instances", func() {
+			Expect(provision.RecordCustomDeployment(stateDir, "my-deployment", true)).To(Succeed())
+			Expect(provision.RecordCustomDeployment(stateDir, "other-deployment", true)).To(Succeed())
+			mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{
+				{Name: "cf"},
+				{Name: "my-deployment", VMs: []bosh.VMStatus{{ProcessState: "running"}, {ProcessState: "failing"}}},
+			}, nil)
+			gomock.InOrder(
+				mockUI.EXPECT().Say("%-24s %-14s %s", "NAME", "STATE", "INSTANCES"),
+				mockUI.EXPECT().Say("%-24s %-14s %s", "my-deployment", "deployed", "1 of 2 running"),
+				mockUI.EXPECT().Say("%-24s %-14s %s", "other-deployment", "not deployed", ""),
+			)
+
+			Expect(deploymentsCmd.Execute()).To(Succeed())
+		})
+
+		It("says when nothing was deployed", func() {
+			mockUI.EXPECT().Say("No deployments were added with cf dev deploy")
+
+			Expect(deploymentsCmd.Execute()).To(Succeed())
+		})
+	})
+})
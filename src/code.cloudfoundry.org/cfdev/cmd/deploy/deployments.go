@@ -0,0 +1,74 @@
+package deploy
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/provision"
+	"github.com/spf13/cobra"
+)
+
+type Deployments struct {
+	UI          UI
+	Provisioner Provisioner
+	StateDir    string
+}
+
+const format = "%-24s %-14s %s"
+
+func (d *Deployments) Cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deployments",
+		Short: "List the deployments added with cf dev deploy",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := d.Execute(); err != nil {
+				return errors.SafeWrap(err, "cf dev deployments")
+			}
+			return nil
+		},
+	}
+}
+
+func (d *Deployments) Execute() error {
+	names := provision.CustomDeployments(d.StateDir)
+	if len(names) == 0 {
+		d.UI.Say("No deployments were added with cf dev deploy")
+		return nil
+	}
+
+	deployments, err := d.Provisioner.Deployments()
+	if err != nil {
+		return err
+	}
+
+	d.UI.Say(format, "NAME", "STATE", "INSTANCES")
+	for _, name := range names {
+		status, found := find(deployments, name)
+		if !found {
+			d.UI.Say(format, name, "not deployed", "")
+			continue
+		}
+		d.UI.Say(format, name, "deployed", instances(status))
+	}
+	return nil
+}
+
+func find(deployments []bosh.DeploymentStatus, name string) (bosh.DeploymentStatus, bool) {
+	for _, deployment := range deployments {
+		if deployment.Name == name {
+			return deployment, true
+		}
+	}
+	return bosh.DeploymentStatus{}, false
+}
+
+func instances(status bosh.DeploymentStatus) string {
+	running := 0
+	for _, vm := range status.VMs {
+		if vm.ProcessState == "running" {
+			running++
+		}
+	}
+	return fmt.Sprintf("%d of %d running", running, len(status.VMs))
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: code.cloudfoundry.org/cfdev/cmd/deploy (interfaces: Provisioner)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	bosh "code.cloudfoundry.org/cfdev/bosh"
+	provision "code.cloudfoundry.org/cfdev/provision"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockProvisioner is a mock of Provisioner interface
+type MockProvisioner struct {
+	ctrl     *gomock.Controller
+	recorder *MockProvisionerMockRecorder
+}
+
+// MockProvisionerMockRecorder is the mock recorder for MockProvisioner
+type MockProvisionerMockRecorder struct {
+	mock *MockProvisioner
+}
+
+// NewMockProvisioner creates a new mock instance
+func NewMockProvisioner(ctrl *gomock.Controller) *MockProvisioner {
+	mock := &MockProvisioner{ctrl: ctrl}
+	mock.recorder = &MockProvisionerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProvisioner) EXPECT() *MockProvisionerMockRecorder {
+	return m.recorder
+}
+
+// DeleteDeployment mocks base method
+func (m *MockProvisioner) DeleteDeployment(arg0 string) error {
+	ret := m.ctrl.Call(m, "DeleteDeployment", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeployment indicates an expected call of DeleteDeployment
+func (mr *MockProvisionerMockRecorder) DeleteDeployment(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeployment", reflect.TypeOf((*MockProvisioner)(nil).DeleteDeployment), arg0)
+}
+
+// DeployManifest mocks base method
+func (m *MockProvisioner) DeployManifest(arg0, arg1 string, arg2 []string) error {
+	ret := m.ctrl.Call(m, "DeployManifest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeployManifest indicates an expected call of DeployManifest
+func (mr *MockProvisionerMockRecorder) DeployManifest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployManifest", reflect.TypeOf((*MockProvisioner)(nil).DeployManifest), arg0, arg1, arg2)
+}
+
+// Deployments mocks base method
+func (m *MockProvisioner) Deployments() ([]bosh.DeploymentStatus, error) {
+	ret := m.ctrl.Call(m, "Deployments")
+	ret0, _ := ret[0].([]bosh.DeploymentStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deployments indicates an expected call of Deployments
+func (mr *MockProvisionerMockRecorder) Deployments() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deployments", reflect.TypeOf((*MockProvisioner)(nil).Deployments))
+}
+
+// GetServices mocks base method
+func (m *MockProvisioner) GetServices() ([]provision.Service, string, error) {
+	ret := m.ctrl.Call(m, "GetServices")
+	ret0, _ := ret[0].([]provision.Service)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetServices indicates an expected call of GetServices
+func (mr *MockProvisionerMockRecorder) GetServices() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServices", reflect.TypeOf((*MockProvisioner)(nil).GetServices))
+}
+
+// ReportProgress mocks base method
+func (m *MockProvisioner) ReportProgress(arg0 provision.UI, arg1 string) {
+	m.ctrl.Call(m, "ReportProgress", arg0, arg1)
+}
+
+// ReportProgress indicates an expected call of ReportProgress
+func (mr *MockProvisionerMockRecorder) ReportProgress(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportProgress", reflect.TypeOf((*MockProvisioner)(nil).ReportProgress), arg0, arg1)
+}
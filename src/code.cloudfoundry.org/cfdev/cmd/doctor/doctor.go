@@ -0,0 +1,72 @@
+package doctor
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/host"
+	"github.com/spf13/cobra"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/doctor UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+//go:generate mockgen -package mocks -destination mocks/host.go code.cloudfoundry.org/cfdev/cmd/doctor Host
+type Host interface {
+	Check(host.Requirements) []host.Failure
+}
+
+type Doctor struct {
+	UI     UI
+	Host   Host
+	Config config.Config
+}
+
+func (d *Doctor) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with the host that prevent CF Dev from starting",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "network",
+		Short: "Check the CF Dev addresses against the host routes and interfaces",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := d.Network(); err != nil {
+				return errors.SafeWrap(err, "cf dev doctor network")
+			}
+			return nil
+		},
+	})
+	return cmd
+}
+
+func (d *Doctor) Network() error {
+	networks := host.VMNetworks(d.Config.BoshDirectorIP, d.Config.CFRouterIP, d.Config.ContainerNetwork)
+	failures := d.Host.Check(host.Requirements{Networks: networks})
+
+	for _, network := range networks {
+		d.UI.Say("%-18s %s", network.Name+":", network.CIDR)
+	}
+
+	if len(failures) == 0 {
+		d.UI.Say("No conflicts with the host routes or interfaces")
+		return nil
+	}
+
+	fatal := 0
+	for _, failure := range failures {
+		if failure.Severity == host.Fatal {
+			fatal++
+			d.UI.Say("CONFLICT: %s", failure.Error())
+		} else {
+			d.UI.Say("WARNING: %s", failure.Error())
+		}
+	}
+	if fatal > 0 {
+		return fmt.Errorf("found %d network conflicts", fatal)
+	}
+	return nil
+}
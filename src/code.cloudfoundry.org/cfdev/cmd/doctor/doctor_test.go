@@ -0,0 +1,64 @@
+package doctor_test
+
+import (
+	"code.cloudfoundry.org/cfdev/cmd/doctor"
+	"code.cloudfoundry.org/cfdev/cmd/doctor/mocks"
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/host"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("doctor network", func() {
+	var (
+		mockController *gomock.Controller
+		mockUI         *mocks.MockUI
+		mockHost       *mocks.MockHost
+		cmd            *doctor.Doctor
+		networks       []host.Network
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockController)
+		mockHost = mocks.NewMockHost(mockController)
+
+		cmd = &doctor.Doctor{
+			UI:   mockUI,
+			Host: mockHost,
+			Config: config.Config{
+				BoshDirectorIP:   "10.245.0.2",
+				CFRouterIP:       "10.144.0.34",
+				ContainerNetwork: "10.246.0.0/16",
+			},
+		}
+		networks = host.VMNetworks("10.245.0.2", "10.144.0.34", "10.246.0.0/16")
+
+		mockUI.EXPECT().Say("%-18s %s", "BOSH director IP:", "10.245.0.2")
+		mockUI.EXPECT().Say("%-18s %s", "router IP:", "10.144.0.34")
+		mockUI.EXPECT().Say("%-18s %s", "container network:", "10.246.0.0/16")
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("reports when nothing conflicts", func() {
+		mockHost.EXPECT().Check(host.Requirements{Networks: networks})
+		mockUI.EXPECT().Say("No conflicts with the host routes or interfaces")
+
+		Expect(cmd.Network()).To(Succeed())
+	})
+
+	It("reports every conflict and fails", func() {
+		mockHost.EXPECT().Check(host.Requirements{Networks: networks}).Return([]host.Failure{
+			{Severity: host.Fatal, Message: "some-conflict", Remediation: "Some remediation"},
+			{Severity: host.Warning, Message: "some-warning"},
+		})
+		mockUI.EXPECT().Say("CONFLICT: %s", "some-conflict. Some remediation")
+		mockUI.EXPECT().Say("WARNING: %s", "some-warning")
+
+		Expect(cmd.Network()).To(MatchError("found 1 network conflicts"))
+	})
+})
@@ -0,0 +1,46 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: code.cloudfoundry.org/cfdev/cmd/doctor (interfaces: Host)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	host "code.cloudfoundry.org/cfdev/host"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockHost is a mock of Host interface
+type MockHost struct {
+	ctrl     *gomock.Controller
+	recorder *MockHostMockRecorder
+}
+
+// MockHostMockRecorder is the mock recorder for MockHost
+type MockHostMockRecorder struct {
+	mock *MockHost
+}
+
+// NewMockHost creates a new mock instance
+func NewMockHost(ctrl *gomock.Controller) *MockHost {
+	mock := &MockHost{ctrl: ctrl}
+	mock.recorder = &MockHostMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHost) EXPECT() *MockHostMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method
+func (m *MockHost) Check(arg0 host.Requirements) []host.Failure {
+	ret := m.ctrl.Call(m, "Check", arg0)
+	ret0, _ := ret[0].([]host.Failure)
+	return ret0
+}
+
+// Check indicates an expected call of Check
+func (mr *MockHostMockRecorder) Check(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockHost)(nil).Check), arg0)
+}
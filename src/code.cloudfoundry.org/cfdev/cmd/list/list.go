@@ -0,0 +1,57 @@
+package list
+
+import (
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/errors"
+	"github.com/spf13/cobra"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/list UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+type List struct {
+	UI      UI
+	Config  config.Config
+	Running func(config.Config) (bool, error)
+}
+
+const format = "%-1s %-16s %-8s %-16s %-16s %s"
+
+func (l *List) Cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the CF Dev environments",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return l.Execute()
+		},
+	}
+}
+
+func (l *List) Execute() error {
+	envs, err := l.Config.Environments()
+	if err != nil {
+		return errors.SafeWrap(err, "cf dev list")
+	}
+
+	l.UI.Say(format, "", "NAME", "STATE", "GARDEN", "BOSH DIRECTOR", "ROUTER")
+	for _, env := range envs {
+		current := ""
+		if env.Env == l.Config.Env {
+			current = "*"
+		}
+		l.UI.Say(format, current, env.EnvName(), l.state(env), env.GardenAddr(), env.BoshDirectorIP, env.CFRouterIP)
+	}
+	return nil
+}
+
+func (l *List) state(env config.Config) string {
+	running, err := l.Running(env)
+	if err != nil {
+		return "unknown"
+	} else if running {
+		return "running"
+	}
+	return "stopped"
+}
@@ -0,0 +1,92 @@
+package list_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/cfdev/cmd/list"
+	"code.cloudfoundry.org/cfdev/cmd/list/mocks"
+	"code.cloudfoundry.org/cfdev/config"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("list", func() {
+	var (
+		mockController *gomock.Controller
+		mockUI         *mocks.MockUI
+		cfdevHome      string
+		cfg            config.Config
+		cmd            *list.List
+		running        map[string]bool
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockController)
+
+		var err error
+		cfdevHome, err = ioutil.TempDir("", "cfdev-home")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg, err = config.Config{
+			CFDevHome: cfdevHome,
+			StateDir:  filepath.Join(cfdevHome, "state", "qemu"),
+		}.ForEnv(config.DefaultEnv)
+		Expect(err).NotTo(HaveOccurred())
+
+		stable, err := cfg.ForEnv("stable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stable.SaveEnv()).To(Succeed())
+
+		running = map[string]bool{"cfdev-stable": true}
+		cmd = &list.List{
+			UI:     mockUI,
+			Config: cfg,
+			Running: func(env config.Config) (bool, error) {
+				if env.VMName() == "cfdev-broken" {
+					return false, errors.New("some-error")
+				}
+				return running[env.VMName()], nil
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+		os.RemoveAll(cfdevHome)
+	})
+
+	It("lists the default environment and the saved environments", func() {
+		gomock.InOrder(
+			mockUI.EXPECT().Say("%-1s %-16s %-8s %-16s %-16s %s", "", "NAME", "STATE", "GARDEN", "BOSH DIRECTOR", "ROUTER"),
+			mockUI.EXPECT().Say("%-1s %-16s %-8s %-16s %-16s %s", "*", "default", "stopped", "localhost:8888", "10.245.0.2", "10.144.0.34"),
+			mockUI.EXPECT().Say("%-1s %-16s %-8s %-16s %-16s %s", "", "stable", "running", "localhost:8889", "10.245.1.2", "10.144.1.34"),
+		)
+
+		Expect(cmd.Execute()).To(Succeed())
+	})
+
+	Context("when the state of an environment cannot be determined", func() {
+		BeforeEach(func() {
+			broken, err := cfg.ForEnv("broken")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(broken.SaveEnv()).To(Succeed())
+			cmd.Config = broken
+		})
+
+		It("reports it as unknown and marks the current environment", func() {
+			gomock.InOrder(
+				mockUI.EXPECT().Say("%-1s %-16s %-8s %-16s %-16s %s", "", "NAME", "STATE", "GARDEN", "BOSH DIRECTOR", "ROUTER"),
+				mockUI.EXPECT().Say("%-1s %-16s %-8s %-16s %-16s %s", "", "default", "stopped", "localhost:8888", "10.245.0.2", "10.144.0.34"),
+				mockUI.EXPECT().Say("%-1s %-16s %-8s %-16s %-16s %s", "*", "broken", "unknown", "localhost:8890", "10.245.2.2", "10.144.2.34"),
+				mockUI.EXPECT().Say("%-1s %-16s %-8s %-16s %-16s %s", "", "stable", "running", "localhost:8889", "10.245.1.2", "10.144.1.34"),
+			)
+
+			Expect(cmd.Execute()).To(Succeed())
+		})
+	})
+})
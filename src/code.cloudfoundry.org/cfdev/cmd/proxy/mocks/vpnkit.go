@@ -0,0 +1,45 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: code.cloudfoundry.org/cfdev/cmd/proxy (interfaces: VpnKit)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockVpnKit is a mock of VpnKit interface
+type MockVpnKit struct {
+	ctrl     *gomock.Controller
+	recorder *MockVpnKitMockRecorder
+}
+
+// MockVpnKitMockRecorder is the mock recorder for MockVpnKit
+type MockVpnKitMockRecorder struct {
+	mock *MockVpnKit
+}
+
+// NewMockVpnKit creates a new mock instance
+func NewMockVpnKit(ctrl *gomock.Controller) *MockVpnKit {
+	mock := &MockVpnKit{ctrl: ctrl}
+	mock.recorder = &MockVpnKitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockVpnKit) EXPECT() *MockVpnKitMockRecorder {
+	return m.recorder
+}
+
+// UpdateProxy mocks base method
+func (m *MockVpnKit) UpdateProxy(arg0 string) error {
+	ret := m.ctrl.Call(m, "UpdateProxy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProxy indicates an expected call of UpdateProxy
+func (mr *MockVpnKitMockRecorder) UpdateProxy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProxy", reflect.TypeOf((*MockVpnKit)(nil).UpdateProxy), arg0)
+}
@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/env"
+	"code.cloudfoundry.org/cfdev/errors"
+	"github.com/spf13/cobra"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/proxy UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+//go:generate mockgen -package mocks -destination mocks/vpnkit.go code.cloudfoundry.org/cfdev/cmd/proxy VpnKit
+type VpnKit interface {
+	UpdateProxy(systemDomain string) error
+}
+
+//go:generate mockgen -package mocks -destination mocks/provision.go code.cloudfoundry.org/cfdev/cmd/proxy Provisioner
+type Provisioner interface {
+	Ping() error
+	SetProxyEnvironment(env.ProxyConfig, string) error
+}
+
+type Proxy struct {
+	UI           UI
+	VpnKit       VpnKit
+	Provisioner  Provisioner
+	Config       config.Config
+	SystemDomain string
+}
+
+func (p *Proxy) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Manage the proxy the vm and the apps reach the internet through",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "sync",
+		Short: "Apply the current host proxy env vars to the vm and the apps",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := p.Sync(); err != nil {
+				return errors.SafeWrap(err, "cf dev proxy sync")
+			}
+			return nil
+		},
+	})
+	return cmd
+}
+
+func (p *Proxy) Sync() error {
+	if err := p.Provisioner.Ping(); err != nil {
+		return errors.SafeWrap(err, "CF Dev is not running")
+	}
+
+	proxy, err := env.BuildProxy(p.Config.BoshDirectorIP, p.Config.CFRouterIP, p.Config.HostIP, p.SystemDomain)
+	if err != nil {
+		return err
+	}
+	if proxy.HTTP != nil || proxy.HTTPS != nil {
+		if p.VpnKit == nil {
+			return fmt.Errorf("the VM cannot reach the internet through the proxy %s on linux, unset http_proxy and https_proxy", proxy)
+		}
+		p.UI.Say("Using proxy %s", proxy)
+	} else {
+		p.UI.Say("No proxy is set on the host, removing the proxy settings")
+	}
+
+	if p.VpnKit != nil {
+		if err := p.VpnKit.UpdateProxy(p.SystemDomain); err != nil {
+			return errors.SafeWrap(err, "failed to update the vpnkit proxy config")
+		}
+	}
+	if err := p.Provisioner.SetProxyEnvironment(proxy.AppConfig(), p.SystemDomain); err != nil {
+		return errors.SafeWrap(err, "failed to set the proxy for apps")
+	}
+
+	p.UI.Say("Restart running apps to pick up the proxy settings")
+	return nil
+}
@@ -11,23 +11,32 @@ import (
 
 	b2 "code.cloudfoundry.org/cfdev/cmd/bosh"
 	b3 "code.cloudfoundry.org/cfdev/cmd/catalog"
+	b10 "code.cloudfoundry.org/cfdev/cmd/config"
+	b14 "code.cloudfoundry.org/cfdev/cmd/deploy"
+	b11 "code.cloudfoundry.org/cfdev/cmd/doctor"
 	b4 "code.cloudfoundry.org/cfdev/cmd/download"
+	b12 "code.cloudfoundry.org/cfdev/cmd/list"
 	b8 "code.cloudfoundry.org/cfdev/cmd/logs"
+	b15 "code.cloudfoundry.org/cfdev/cmd/proxy"
+	b13 "code.cloudfoundry.org/cfdev/cmd/services"
+	b16 "code.cloudfoundry.org/cfdev/cmd/snapshot"
 	b5 "code.cloudfoundry.org/cfdev/cmd/start"
+	b9 "code.cloudfoundry.org/cfdev/cmd/status"
 	b6 "code.cloudfoundry.org/cfdev/cmd/stop"
 	b7 "code.cloudfoundry.org/cfdev/cmd/telemetry"
 	b1 "code.cloudfoundry.org/cfdev/cmd/version"
 	"code.cloudfoundry.org/cfdev/config"
 	"code.cloudfoundry.org/cfdev/daemon"
+	"code.cloudfoundry.org/cfdev/host"
 	"code.cloudfoundry.org/cfdev/hypervisor"
 	"code.cloudfoundry.org/cfdev/iso"
 	"code.cloudfoundry.org/cfdev/network"
 	"code.cloudfoundry.org/cfdev/provision"
 	"code.cloudfoundry.org/cfdev/resource"
 	"code.cloudfoundry.org/cfdev/resource/progress"
+	"code.cloudfoundry.org/cfdev/snapshot"
 	cfdevdClient "code.cloudfoundry.org/cfdevd/client"
 	"github.com/spf13/cobra"
-	"code.cloudfoundry.org/cfdev/host"
 )
 
 type UI interface {
@@ -50,10 +59,16 @@ type Toggle interface {
 	SetProp(k, v string) error
 }
 
+// vmRunning reports whether the vm of the given environment is running
+func vmRunning(env config.Config) (bool, error) {
+	return (&hypervisor.LinuxKit{Config: env, DaemonRunner: daemon.New(env.CFDevHome)}).IsRunning(env.VMName())
+}
+
 func NewRoot(exit chan struct{}, ui UI, config config.Config, analyticsClient AnalyticsClient, analyticsToggle Toggle) *cobra.Command {
 	root := &cobra.Command{Use: "cf", SilenceUsage: true, SilenceErrors: true}
 	root.PersistentFlags().Bool("help", false, "")
 	root.PersistentFlags().Lookup("help").Hidden = true
+	root.PersistentFlags().String("env", config.EnvName(), "name of the CF Dev environment to use, also read from CFDEV_ENV")
 	lctl := daemon.New(config.CFDevHome)
 
 	usageTemplate := strings.Replace(root.UsageTemplate(), "\n"+`Use "{{.CommandPath}} [command] --help" for more information about a command.`, "", -1)
@@ -72,6 +87,41 @@ func NewRoot(exit chan struct{}, ui UI, config config.Config, analyticsClient An
 	linuxkit := &hypervisor.LinuxKit{Config: config, DaemonRunner: lctl}
 	vpnkit := &network.VpnKit{Config: config, DaemonRunner: lctl}
 
+	startCmd := &b5.Start{
+		Exit:            exit,
+		LocalExit:       make(chan string, 3),
+		UI:              ui,
+		Config:          config,
+		Cache:           cache,
+		Analytics:       analyticsClient,
+		AnalyticsToggle: analyticsToggle,
+		HostNet: &network.HostNet{
+			CfdevdClient: cfdevdClient.New("CFD3V", config.CFDevDSocketPath),
+		},
+		Host: &host.Host{UI: ui},
+		CFDevD: &network.CFDevD{
+			ExecutablePath: filepath.Join(config.CacheDir, "cfdevd"),
+			AliasIPs:       config.AliasIPs(),
+		},
+		VpnKit:      vpnkit,
+		Hypervisor:  linuxkit,
+		Provisioner: provision.NewController(config),
+		IsoReader:   iso.New(),
+		Snapshots:   snapshot.New(config.EnvDir),
+	}
+	stopCmd := &b6.Stop{
+		Config:     config,
+		Analytics:  analyticsClient,
+		Hypervisor: linuxkit,
+		HostNet: &network.HostNet{
+			CfdevdClient: cfdevdClient.New("CFD3V", config.CFDevDSocketPath),
+		},
+		Host:         &host.Host{UI: ui},
+		VpnKit:       vpnkit,
+		CfdevdClient: cfdevdClient.New("CFD3V", config.CFDevDSocketPath),
+		Running:      vmRunning,
+	}
+
 	dev := &cobra.Command{
 		Use:           "dev",
 		Short:         "Start and stop a single vm CF deployment running on your workstation",
@@ -89,7 +139,7 @@ func NewRoot(exit chan struct{}, ui UI, config config.Config, analyticsClient An
 			Exit:        exit,
 			UI:          ui,
 			StateDir:    config.StateDir,
-			Provisioner: provision.NewController(),
+			Provisioner: provision.NewController(config),
 		},
 		&b3.Catalog{
 			UI:     ui,
@@ -100,43 +150,80 @@ func NewRoot(exit chan struct{}, ui UI, config config.Config, analyticsClient An
 			UI:     ui,
 			Config: config,
 		},
-		&b5.Start{
-			Exit:            exit,
-			LocalExit:       make(chan string, 3),
-			UI:              ui,
-			Config:          config,
-			Cache:           cache,
-			Analytics:       analyticsClient,
-			AnalyticsToggle: analyticsToggle,
-			HostNet: &network.HostNet{
-				CfdevdClient: cfdevdClient.New("CFD3V", config.CFDevDSocketPath),
-			},
-			Host:        &host.Host{},
-			CFDevD:      &network.CFDevD{ExecutablePath: filepath.Join(config.CacheDir, "cfdevd")},
-			VpnKit:      vpnkit,
-			Hypervisor:  linuxkit,
-			Provisioner: provision.NewController(),
-			IsoReader:   iso.New(),
-		},
-		&b6.Stop{
-			Config:     config,
-			Analytics:  analyticsClient,
-			Hypervisor: linuxkit,
-			HostNet: &network.HostNet{
-				CfdevdClient: cfdevdClient.New("CFD3V", config.CFDevDSocketPath),
-			},
-			Host:        &host.Host{},
-			VpnKit:       vpnkit,
-			CfdevdClient: cfdevdClient.New("CFD3V", config.CFDevDSocketPath),
-		},
+		startCmd,
+		stopCmd,
 		&b7.Telemetry{
 			UI:              ui,
 			AnalyticsToggle: analyticsToggle,
 		},
 		&b8.Logs{
-			Provisioner: provision.NewController(),
+			Provisioner: provision.NewController(config),
 			UI:          ui,
 		},
+		&b9.Status{
+			UI:           ui,
+			Hypervisor:   linuxkit,
+			VpnKit:       vpnkit,
+			Provisioner:  provision.NewController(config),
+			SystemDomain: config.SystemDomain(),
+			VMName:       config.VMName(),
+			StateDir:     config.StateDir,
+		},
+		&b10.Config{
+			UI:        ui,
+			Config:    config,
+			IsoReader: iso.New(),
+		},
+		&b11.Doctor{
+			UI:     ui,
+			Host:   &host.Host{UI: ui},
+			Config: config,
+		},
+		&b12.List{
+			UI:      ui,
+			Config:  config,
+			Running: vmRunning,
+		},
+		&b13.Services{
+			UI:          ui,
+			Provisioner: provision.NewController(config),
+			StateDir:    config.StateDir,
+		},
+		&b14.Deploy{
+			UI:          ui,
+			Provisioner: provision.NewController(config),
+			StateDir:    config.StateDir,
+		},
+		&b14.Deployments{
+			UI:          ui,
+			Provisioner: provision.NewController(config),
+			StateDir:    config.StateDir,
+		},
+		&b15.Proxy{
+			UI:           ui,
+			VpnKit:       vpnkit,
+			Provisioner:  provision.NewController(config),
+			Config:       config,
+			SystemDomain: config.SystemDomain(),
+		},
+		&b16.Snapshot{
+			UI:         ui,
+			Hypervisor: linuxkit,
+			Store:      snapshot.New(config.EnvDir),
+			Config:     config,
+			Start: func(name string) error {
+				return startCmd.Execute(b5.Args{Snapshot: name})
+			},
+			Stop: func() error {
+				return stopCmd.RunE(nil, nil)
+			},
+			Resume: func() error {
+				return startCmd.Execute(b5.Args{Resume: true})
+			},
+			Provisioned: func() bool {
+				return b5.Provisioned(config.StateDir)
+			},
+		},
 	} {
 		dev.AddCommand(cmd.Cmd())
 	}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"path/filepath"
+
+	b2 "code.cloudfoundry.org/cfdev/cmd/bosh"
+	b3 "code.cloudfoundry.org/cfdev/cmd/catalog"
+	b10 "code.cloudfoundry.org/cfdev/cmd/config"
+	b14 "code.cloudfoundry.org/cfdev/cmd/deploy"
+	b11 "code.cloudfoundry.org/cfdev/cmd/doctor"
+	b4 "code.cloudfoundry.org/cfdev/cmd/download"
+	b12 "code.cloudfoundry.org/cfdev/cmd/list"
+	b8 "code.cloudfoundry.org/cfdev/cmd/logs"
+	b15 "code.cloudfoundry.org/cfdev/cmd/proxy"
+	b13 "code.cloudfoundry.org/cfdev/cmd/services"
+	b16 "code.cloudfoundry.org/cfdev/cmd/snapshot"
+	b5 "code.cloudfoundry.org/cfdev/cmd/start"
+	b9 "code.cloudfoundry.org/cfdev/cmd/status"
+	b6 "code.cloudfoundry.org/cfdev/cmd/stop"
+	b7 "code.cloudfoundry.org/cfdev/cmd/telemetry"
+	b1 "code.cloudfoundry.org/cfdev/cmd/version"
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/daemon"
+	"code.cloudfoundry.org/cfdev/host"
+	"code.cloudfoundry.org/cfdev/hypervisor"
+	"code.cloudfoundry.org/cfdev/iso"
+	"code.cloudfoundry.org/cfdev/network"
+	"code.cloudfoundry.org/cfdev/provision"
+	"code.cloudfoundry.org/cfdev/resource"
+	"code.cloudfoundry.org/cfdev/resource/progress"
+	"code.cloudfoundry.org/cfdev/snapshot"
+	"github.com/spf13/cobra"
+)
+
+type UI interface {
+	Say(message string, args ...interface{})
+	Writer() io.Writer
+}
+
+type cmdBuilder interface {
+	Cmd() *cobra.Command
+}
+
+type AnalyticsClient interface {
+	Event(event string, data ...map[string]interface{}) error
+	PromptOptIn() error
+}
+
+type Toggle interface {
+	Get() bool
+	Set(value bool) error
+	SetProp(k, v string) error
+}
+
+// vmRunning reports whether the vm of the given environment is running
+func vmRunning(env config.Config) (bool, error) {
+	return (&hypervisor.QEMU{Config: env}).IsRunning(env.VMName())
+}
+
+func NewRoot(exit chan struct{}, ui UI, config config.Config, analyticsClient AnalyticsClient, analyticsToggle Toggle) *cobra.Command {
+	root := &cobra.Command{Use: "cf", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().Bool("help", false, "")
+	root.PersistentFlags().Lookup("help").Hidden = true
+	root.PersistentFlags().String("env", config.EnvName(), "name of the CF Dev environment to use, also read from CFDEV_ENV")
+	var lctl network.DaemonRunner = daemon.NewProcess(config.CFDevHome)
+	if daemon.SystemdAvailable() {
+		lctl = daemon.NewSystemd("")
+	}
+
+	usageTemplate := strings.Replace(root.UsageTemplate(), "\n"+`Use "{{.CommandPath}} [command] --help" for more information about a command.`, "", -1)
+	root.SetUsageTemplate(usageTemplate)
+
+	skipVerify := strings.ToLower(os.Getenv("CFDEV_SKIP_ASSET_CHECK"))
+	writer := ui.Writer()
+	cache := &resource.Cache{
+		Dir:                   config.CacheDir,
+		HttpDo:                http.DefaultClient.Do,
+		SkipAssetVerification: skipVerify == "true",
+		Progress:              progress.New(writer),
+		RetryWait:             time.Second,
+		Writer:                writer,
+	}
+	qemu := &hypervisor.QEMU{Config: config}
+	// only stop uses vpnkit, to remove the daemon earlier versions started
+	// next to the vm
+	vpnkit := &network.VpnKit{Config: config, DaemonRunner: lctl}
+
+	startCmd := &b5.Start{
+		Exit:            exit,
+		LocalExit:       make(chan string, 3),
+		UI:              ui,
+		Config:          config,
+		Cache:           cache,
+		Analytics:       analyticsClient,
+		AnalyticsToggle: analyticsToggle,
+		HostNet:         &network.HostNet{},
+		Host:            &host.Host{UI: ui},
+		CFDevD: &network.CFDevD{
+			ExecutablePath: filepath.Join(config.CacheDir, "cfdevd"),
+			AliasIPs:       config.AliasIPs(),
+		},
+		Hypervisor:  qemu,
+		Provisioner: provision.NewController(config),
+		IsoReader:   iso.New(),
+		Snapshots:   snapshot.New(config.EnvDir),
+	}
+	stopCmd := &b6.Stop{
+		Config:     config,
+		Analytics:  analyticsClient,
+		Hypervisor: qemu,
+		VpnKit:     vpnkit,
+		HostNet:    &network.HostNet{},
+		Host:       &host.Host{UI: ui},
+		Running:    vmRunning,
+	}
+
+	dev := &cobra.Command{
+		Use:           "dev",
+		Short:         "Start and stop a single vm CF deployment running on your workstation",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(dev)
+
+	for _, cmd := range []cmdBuilder{
+		&b1.Version{
+			UI:      ui,
+			Version: config.CliVersion,
+		},
+		&b2.Bosh{
+			Exit:        exit,
+			UI:          ui,
+			StateDir:    config.StateDir,
+			Provisioner: provision.NewController(config),
+		},
+		&b3.Catalog{
+			UI:     ui,
+			Config: config,
+		},
+		&b4.Download{
+			Exit:   exit,
+			UI:     ui,
+			Config: config,
+		},
+		startCmd,
+		stopCmd,
+		&b7.Telemetry{
+			UI:              ui,
+			AnalyticsToggle: analyticsToggle,
+		},
+		&b8.Logs{
+			Provisioner: provision.NewController(config),
+			UI:          ui,
+		},
+		&b9.Status{
+			UI:           ui,
+			Hypervisor:   qemu,
+			Provisioner:  provision.NewController(config),
+			SystemDomain: config.SystemDomain(),
+			VMName:       config.VMName(),
+			StateDir:     config.StateDir,
+		},
+		&b10.Config{
+			UI:        ui,
+			Config:    config,
+			IsoReader: iso.New(),
+		},
+		&b11.Doctor{
+			UI:     ui,
+			Host:   &host.Host{UI: ui},
+			Config: config,
+		},
+		&b12.List{
+			UI:      ui,
+			Config:  config,
+			Running: vmRunning,
+		},
+		&b13.Services{
+			UI:          ui,
+			Provisioner: provision.NewController(config),
+			StateDir:    config.StateDir,
+		},
+		&b14.Deploy{
+			UI:          ui,
+			Provisioner: provision.NewController(config),
+			StateDir:    config.StateDir,
+		},
+		&b14.Deployments{
+			UI:          ui,
+			Provisioner: provision.NewController(config),
+			StateDir:    config.StateDir,
+		},
+		&b15.Proxy{
+			UI:           ui,
+			Provisioner:  provision.NewController(config),
+			Config:       config,
+			SystemDomain: config.SystemDomain(),
+		},
+		&b16.Snapshot{
+			UI:         ui,
+			Hypervisor: qemu,
+			Store:      snapshot.New(config.EnvDir),
+			Config:     config,
+			Start: func(name string) error {
+				return startCmd.Execute(b5.Args{Snapshot: name})
+			},
+			Stop: func() error {
+				return stopCmd.RunE(nil, nil)
+			},
+			Resume: func() error {
+				return startCmd.Execute(b5.Args{Resume: true})
+			},
+			Provisioned: func() bool {
+				return b5.Provisioned(config.StateDir)
+			},
+		},
+	} {
+		dev.AddCommand(cmd.Cmd())
+	}
+
+	dev.AddCommand(&cobra.Command{
+		Use:   "help [command]",
+		Short: "Help about any command",
+		Run: func(c *cobra.Command, args []string) {
+			cmd, _, _ := dev.Find(args)
+			cmd.Help()
+		},
+	})
+
+	return root
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: code.cloudfoundry.org/cfdev/cmd/services (interfaces: Provisioner)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	bosh "code.cloudfoundry.org/cfdev/bosh"
+	provision "code.cloudfoundry.org/cfdev/provision"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockProvisioner is a mock of Provisioner interface
+type MockProvisioner struct {
+	ctrl     *gomock.Controller
+	recorder *MockProvisionerMockRecorder
+}
+
+// MockProvisionerMockRecorder is the mock recorder for MockProvisioner
+type MockProvisionerMockRecorder struct {
+	mock *MockProvisioner
+}
+
+// NewMockProvisioner creates a new mock instance
+func NewMockProvisioner(ctrl *gomock.Controller) *MockProvisioner {
+	mock := &MockProvisioner{ctrl: ctrl}
+	mock.recorder = &MockProvisionerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProvisioner) EXPECT() *MockProvisionerMockRecorder {
+	return m.recorder
+}
+
+// DeleteService mocks base method
+func (m *MockProvisioner) DeleteService(arg0 provision.Service) error {
+	ret := m.ctrl.Call(m, "DeleteService", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteService indicates an expected call of DeleteService
+func (mr *MockProvisionerMockRecorder) DeleteService(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockProvisioner)(nil).DeleteService), arg0)
+}
+
+// DeployServices mocks base method
+func (m *MockProvisioner) DeployServices(arg0 provision.UI, arg1 []provision.Service, arg2 func(provision.Service) error) error {
+	ret := m.ctrl.Call(m, "DeployServices", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeployServices indicates an expected call of DeployServices
+func (mr *MockProvisionerMockRecorder) DeployServices(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployServices", reflect.TypeOf((*MockProvisioner)(nil).DeployServices), arg0, arg1, arg2)
+}
+
+// Deployments mocks base method
+func (m *MockProvisioner) Deployments() ([]bosh.DeploymentStatus, error) {
+	ret := m.ctrl.Call(m, "Deployments")
+	ret0, _ := ret[0].([]bosh.DeploymentStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deployments indicates an expected call of Deployments
+func (mr *MockProvisionerMockRecorder) Deployments() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deployments", reflect.TypeOf((*MockProvisioner)(nil).Deployments))
+}
+
+// GetServices mocks base method
+func (m *MockProvisioner) GetServices() ([]provision.Service, string, error) {
+	ret := m.ctrl.Call(m, "GetServices")
+	ret0, _ := ret[0].([]provision.Service)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetServices indicates an expected call of GetServices
+func (mr *MockProvisionerMockRecorder) GetServices() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServices", reflect.TypeOf((*MockProvisioner)(nil).GetServices))
+}
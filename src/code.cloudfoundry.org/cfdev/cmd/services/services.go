@@ -0,0 +1,167 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/provision"
+	"github.com/spf13/cobra"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/services UI
+type UI interface {
+	Say(message string, args ...interface{})
+	Writer() io.Writer
+}
+
+//go:generate mockgen -package mocks -destination mocks/provision.go code.cloudfoundry.org/cfdev/cmd/services Provisioner
+type Provisioner interface {
+	GetServices() ([]provision.Service, string, error)
+	Deployments() ([]bosh.DeploymentStatus, error)
+	DeployServices(provision.UI, []provision.Service, func(provision.Service) error) error
+	DeleteService(provision.Service) error
+}
+
+type Services struct {
+	UI          UI
+	Provisioner Provisioner
+	StateDir    string
+}
+
+func (s *Services) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "services",
+		Short: "List, deploy and delete the services packaged with the deps iso",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the packaged services and whether they are deployed",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := s.List(); err != nil {
+				return errors.SafeWrap(err, "cf dev services list")
+			}
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "deploy <name>",
+		Short: "Deploy a packaged service and the services it depends on",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := s.Deploy(args[0]); err != nil {
+				return errors.SafeWrap(err, "cf dev services deploy")
+			}
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete the deployment of a packaged service",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := s.Delete(args[0]); err != nil {
+				return errors.SafeWrap(err, "cf dev services delete")
+			}
+			return nil
+		},
+	})
+	return cmd
+}
+
+func (s *Services) List() error {
+	available, _, err := s.Provisioner.GetServices()
+	if err != nil {
+		return errors.SafeWrap(err, "failed to read the services of the deps iso")
+	}
+	deployments, err := s.Provisioner.Deployments()
+	if err != nil {
+		return err
+	}
+
+	if len(available) == 0 {
+		s.UI.Say("The deps iso does not package any services")
+		return nil
+	}
+
+	s.UI.Say("%-20s %-24s %s", "NAME", "DEPLOYMENT", "STATE")
+	for _, service := range available {
+		state := "not deployed"
+		if isDeployed(service.Deployment, deployments) {
+			state = "deployed"
+		}
+		s.UI.Say("%-20s %-24s %s", service.Name, service.Deployment, state)
+	}
+	return nil
+}
+
+// Deploy deploys the service together with the services it depends on that
+// are not deployed yet
+func (s *Services) Deploy(name string) error {
+	available, selected, err := s.find(name)
+	if err != nil {
+		return err
+	}
+	deployments, err := s.Provisioner.Deployments()
+	if err != nil {
+		return err
+	}
+
+	services := []provision.Service{selected[0]}
+	for _, dep := range selected[1:] {
+		if !isDeployed(dep.Deployment, deployments) {
+			services = append(services, dep)
+		}
+	}
+
+	return s.Provisioner.DeployServices(s.UI, services, func(service provision.Service) error {
+		return provision.UpdateSelection(s.StateDir, available, service.Name, true)
+	})
+}
+
+func (s *Services) Delete(name string) error {
+	available, selected, err := s.find(name)
+	if err != nil {
+		return err
+	}
+	service := selected[0]
+	if service.IsErrand {
+		return fmt.Errorf("%s runs as an errand of the %s deployment and cannot be deleted", service.Name, service.Deployment)
+	}
+
+	s.UI.Say("Deleting %s...", service.Name)
+	if err := s.Provisioner.DeleteService(service); err != nil {
+		return err
+	}
+	s.UI.Say("  Done")
+	return provision.UpdateSelection(s.StateDir, available, service.Name, false)
+}
+
+// find returns the named service first, followed by the services it depends
+// on
+func (s *Services) find(name string) ([]provision.Service, []provision.Service, error) {
+	available, _, err := s.Provisioner.GetServices()
+	if err != nil {
+		return nil, nil, errors.SafeWrap(err, "failed to read the services of the deps iso")
+	}
+	if strings.EqualFold(name, provision.AllServices) || strings.EqualFold(name, provision.NoServices) {
+		return nil, nil, fmt.Errorf("'%s' is not a service, name a single service", name)
+	}
+
+	services, err := provision.SelectServices(available, []string{name})
+	if err != nil {
+		return nil, nil, err
+	}
+	return available, services, nil
+}
+
+func isDeployed(deployment string, deployments []bosh.DeploymentStatus) bool {
+	for _, d := range deployments {
+		if d.Name == deployment {
+			return true
+		}
+	}
+	return false
+}
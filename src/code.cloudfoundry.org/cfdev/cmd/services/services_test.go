@@ -0,0 +1,169 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/cmd/services"
+	"code.cloudfoundry.org/cfdev/cmd/services/mocks"
+	"code.cloudfoundry.org/cfdev/provision"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("services", func() {
+	var (
+		mockController  *gomock.Controller
+		mockUI          *mocks.MockUI
+		mockProvisioner *mocks.MockProvisioner
+		stateDir        string
+		available       []provision.Service
+		cmd             *services.Services
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockController)
+		mockProvisioner = mocks.NewMockProvisioner(mockController)
+
+		var err error
+		stateDir, err = ioutil.TempDir("", "state-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		available = []provision.Service{
+			{Name: "Mysql", Deployment: "cf-mysql", Handle: "deploy-mysql", Script: "bin/deploy-mysql"},
+			{Name: "Redis", Deployment: "cf-redis", Handle: "deploy-redis", Script: "bin/deploy-redis"},
+			{Name: "Smoke", Deployment: "cf", IsErrand: true},
+		}
+
+		cmd = &services.Services{
+			UI:          mockUI,
+			Provisioner: mockProvisioner,
+			StateDir:    stateDir,
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+		os.RemoveAll(stateDir)
+	})
+
+	Describe("list", func() {
+		It("lists the packaged services and whether they are deployed", func() {
+			mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+			mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{{Name: "cf"}, {Name: "cf-mysql"}}, nil)
+			gomock.InOrder(
+				mockUI.EXPECT().Say("%-20s %-24s %s", "NAME", "DEPLOYMENT", "STATE"),
+				mockUI.EXPECT().Say("%-20s %-24s %s", "Mysql", "cf-mysql", "deployed"),
+				mockUI.EXPECT().Say("%-20s %-24s %s", "Redis", "cf-redis", "not deployed"),
+				mockUI.EXPECT().Say("%-20s %-24s %s", "Smoke", "cf", "deployed"),
+			)
+
+			Expect(cmd.List()).To(Succeed())
+		})
+
+		It("says when the deps iso has no services", func() {
+			mockProvisioner.EXPECT().GetServices().Return(nil, "", nil)
+			mockProvisioner.EXPECT().Deployments().Return(nil, nil)
+			mockUI.EXPECT().Say("The deps iso does not package any services")
+
+			Expect(cmd.List()).To(Succeed())
+		})
+	})
+
+	Describe("deploy", func() {
+		It("deploys the service and adds it to the selection", func() {
+			Expect(provision.SaveSelection(stateDir, nil)).To(Succeed())
+			mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+			mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{{Name: "cf"}}, nil)
+			mockProvisioner.EXPECT().DeployServices(mockUI, []provision.Service{available[1]}, gomock.Any()).DoAndReturn(
+				func(ui provision.UI, services []provision.Service, deployed func(provision.Service) error) error {
+					return deployed(services[0])
+				},
+			)
+
+			Expect(cmd.Deploy("redis")).To(Succeed())
+
+			names, _ := provision.LoadSelection(stateDir)
+			Expect(names).To(Equal([]string{"Redis"}))
+		})
+
+		Context("when the service depends on other services", func() {
+			BeforeEach(func() {
+				available[1].DependsOn = []string{"Mysql", "Smoke"}
+			})
+
+			It("deploys the dependencies that are not deployed yet", func() {
+				Expect(provision.SaveSelection(stateDir, nil)).To(Succeed())
+				mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+				mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{{Name: "cf"}}, nil)
+				mockProvisioner.EXPECT().DeployServices(mockUI, []provision.Service{available[1], available[0]}, gomock.Any()).DoAndReturn(
+					func(ui provision.UI, services []provision.Service, deployed func(provision.Service) error) error {
+						Expect(deployed(services[1])).To(Succeed())
+						return deployed(services[0])
+					},
+				)
+
+				Expect(cmd.Deploy("redis")).To(Succeed())
+
+				names, _ := provision.LoadSelection(stateDir)
+				Expect(names).To(Equal([]string{"Mysql", "Redis"}))
+			})
+
+			It("leaves out the dependencies that are deployed", func() {
+				mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+				mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{{Name: "cf"}, {Name: "cf-mysql"}}, nil)
+				mockProvisioner.EXPECT().DeployServices(mockUI, []provision.Service{available[1]}, gomock.Any())
+
+				Expect(cmd.Deploy("redis")).To(Succeed())
+			})
+		})
+
+		It("rejects unknown services", func() {
+			mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+
+			Expect(cmd.Deploy("rabbitmq")).To(MatchError(ContainSubstring("unknown service 'rabbitmq'")))
+		})
+
+		It("rejects all and none", func() {
+			mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+
+			Expect(cmd.Deploy("all")).To(MatchError("'all' is not a service, name a single service"))
+		})
+
+		It("returns the deploy error without changing the selection", func() {
+			mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+			mockProvisioner.EXPECT().Deployments().Return(nil, nil)
+			mockProvisioner.EXPECT().DeployServices(mockUI, gomock.Any(), gomock.Any()).Return(errors.New("some-error"))
+
+			Expect(cmd.Deploy("redis")).To(MatchError("some-error"))
+			_, recorded := provision.LoadSelection(stateDir)
+			Expect(recorded).To(BeFalse())
+		})
+	})
+
+	Describe("delete", func() {
+		It("deletes the service deployment and removes it from the selection", func() {
+			mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+			gomock.InOrder(
+				mockUI.EXPECT().Say("Deleting %s...", "Mysql"),
+				mockProvisioner.EXPECT().DeleteService(available[0]),
+				mockUI.EXPECT().Say("  Done"),
+			)
+
+			Expect(cmd.Delete("mysql")).To(Succeed())
+
+			names, _ := provision.LoadSelection(stateDir)
+			Expect(names).To(Equal([]string{"Redis", "Smoke"}))
+		})
+
+		It("refuses to delete errands", func() {
+			mockProvisioner.EXPECT().GetServices().Return(available, "", nil)
+
+			Expect(cmd.Delete("smoke")).To(MatchError("Smoke runs as an errand of the cf deployment and cannot be deleted"))
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package snapshot
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/snapshot"
+	"github.com/spf13/cobra"
+)
+
+const format = "%-24s %-18s %s"
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/snapshot UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+//go:generate mockgen -package mocks -destination mocks/hypervisor.go code.cloudfoundry.org/cfdev/cmd/snapshot Hypervisor
+type Hypervisor interface {
+	Stop(vmName string) error
+	IsRunning(vmName string) (bool, error)
+	DiskPath(vmName string) string
+}
+
+//go:generate mockgen -package mocks -destination mocks/store.go code.cloudfoundry.org/cfdev/cmd/snapshot Store
+type Store interface {
+	Save(name, stateDir, diskPath string) (snapshot.Metadata, error)
+	List() ([]snapshot.Metadata, error)
+	Delete(name string) error
+}
+
+type Snapshot struct {
+	UI         UI
+	Hypervisor Hypervisor
+	Store      Store
+	Config     config.Config
+	// Start starts the environment from a snapshot, as cf dev start does
+	Start func(name string) error
+	// Stop stops the environment, as cf dev stop does
+	Stop func() error
+	// Resume boots the stopped vm again, as cf dev start --resume does
+	Resume func() error
+	// Provisioned tells whether cf dev start ran to the end
+	Provisioned func() bool
+}
+
+func (s *Snapshot) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save and restore the deployed environment",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "save NAME",
+		Short: "Stop CF Dev and save the vm disk and state as a snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := s.Save(args[0]); err != nil {
+				return errors.SafeWrap(err, "cf dev snapshot save")
+			}
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "restore NAME",
+		Short: "Start CF Dev from a snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := s.Restore(args[0]); err != nil {
+				return errors.SafeWrap(err, "cf dev snapshot restore")
+			}
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the saved snapshots",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := s.List(); err != nil {
+				return errors.SafeWrap(err, "cf dev snapshot list")
+			}
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete a snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := s.Store.Delete(args[0]); err != nil {
+				return errors.SafeWrap(err, "cf dev snapshot delete")
+			}
+			s.UI.Say("Deleted snapshot %s", args[0])
+			return nil
+		},
+	})
+	return cmd
+}
+
+// Save stops the vm first so that its disk is consistent, the environment is
+// left stopped. Only a fully deployed environment is saved, as a restore
+// boots it with BOSH and CF deployed.
+func (s *Snapshot) Save(name string) error {
+	if !s.Provisioned() {
+		return errors.SafeWrap(nil, "CF Dev is not fully deployed, finish 'cf dev start' before saving a snapshot")
+	}
+
+	running, err := s.Hypervisor.IsRunning(s.Config.VMName())
+	if err != nil {
+		return errors.SafeWrap(err, "failed to check whether the vm is running")
+	}
+	if running {
+		s.UI.Say("Stopping the VM...")
+		if err := s.Hypervisor.Stop(s.Config.VMName()); err != nil {
+			return errors.SafeWrap(err, "failed to stop the VM")
+		}
+	}
+
+	s.UI.Say("Saving snapshot %s...", name)
+	meta, err := s.Store.Save(name, s.Config.StateDir, s.Hypervisor.DiskPath(s.Config.VMName()))
+	if err != nil && running {
+		s.UI.Say("Saving the snapshot failed, starting the VM again...")
+		if resumeErr := s.Resume(); resumeErr != nil {
+			return errors.SafeWrap(err, fmt.Sprintf("the VM is left stopped as it failed to start again (%s), start it with 'cf dev start --resume'", resumeErr))
+		}
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if running {
+		if err := s.Stop(); err != nil {
+			return err
+		}
+	}
+	s.UI.Say("Saved snapshot %s, restore it with: cf dev snapshot restore %s", meta.Name, meta.Name)
+	return nil
+}
+
+func (s *Snapshot) Restore(name string) error {
+	running, err := s.Hypervisor.IsRunning(s.Config.VMName())
+	if err != nil {
+		return errors.SafeWrap(err, "failed to check whether the vm is running")
+	}
+	if running {
+		return errors.SafeWrap(nil, "CF Dev is running, stop it with 'cf dev stop' before restoring a snapshot")
+	}
+	return s.Start(name)
+}
+
+func (s *Snapshot) List() error {
+	snapshots, err := s.Store.List()
+	if err != nil {
+		return errors.SafeWrap(err, "failed to list the snapshots")
+	}
+	if len(snapshots) == 0 {
+		s.UI.Say("No snapshots, save one with: cf dev snapshot save NAME")
+		return nil
+	}
+
+	s.UI.Say(format, "NAME", "CREATED", "DEPS ISO")
+	for _, meta := range snapshots {
+		s.UI.Say(format, meta.Name, meta.CreatedAt.Local().Format("2006-01-02 15:04"), meta.DepsIsoPath)
+	}
+	return nil
+}
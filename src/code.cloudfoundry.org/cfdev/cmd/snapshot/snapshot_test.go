@@ -0,0 +1,159 @@
+package snapshot_test
+
+import (
+	"errors"
+	"time"
+
+	cmd "code.cloudfoundry.org/cfdev/cmd/snapshot"
+	"code.cloudfoundry.org/cfdev/cmd/snapshot/mocks"
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/snapshot"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("snapshot", func() {
+	var (
+		mockController *gomock.Controller
+		mockUI         *mocks.MockUI
+		mockHypervisor *mocks.MockHypervisor
+		mockStore      *mocks.MockStore
+		started        []string
+		stopped        int
+		resumed        int
+		resumeErr      error
+		provisioned    bool
+		snapshotCmd    *cmd.Snapshot
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockController)
+		mockHypervisor = mocks.NewMockHypervisor(mockController)
+		mockStore = mocks.NewMockStore(mockController)
+		started, stopped, resumed, resumeErr, provisioned = nil, 0, 0, nil, true
+
+		snapshotCmd = &cmd.Snapshot{
+			UI:         mockUI,
+			Hypervisor: mockHypervisor,
+			Store:      mockStore,
+			Config:     config.Config{StateDir: "/state"},
+			Start: func(name string) error {
+				started = append(started, name)
+				return nil
+			},
+			Stop: func() error {
+				stopped++
+				return nil
+			},
+			Resume: func() error {
+				resumed++
+				return resumeErr
+			},
+			Provisioned: func() bool {
+				return provisioned
+			},
+		}
+		mockHypervisor.EXPECT().DiskPath("cfdev").Return("/state/disk.qcow2").AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	Describe("save", func() {
+		It("stops the vm before saving and stops cf dev afterwards", func() {
+			gomock.InOrder(
+				mockHypervisor.EXPECT().IsRunning("cfdev").Return(true, nil),
+				mockUI.EXPECT().Say("Stopping the VM..."),
+				mockHypervisor.EXPECT().Stop("cfdev"),
+				mockUI.EXPECT().Say("Saving snapshot %s...", "clean"),
+				mockStore.EXPECT().Save("clean", "/state", "/state/disk.qcow2").Return(snapshot.Metadata{Name: "clean"}, nil),
+				mockUI.EXPECT().Say("Saved snapshot %s, restore it with: cf dev snapshot restore %s", "clean", "clean"),
+			)
+
+			Expect(snapshotCmd.Save("clean")).To(Succeed())
+			Expect(stopped).To(Equal(1))
+		})
+
+		It("saves a stopped vm as it is", func() {
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(false, nil)
+			mockUI.EXPECT().Say("Saving snapshot %s...", "clean")
+			mockStore.EXPECT().Save("clean", "/state", "/state/disk.qcow2").Return(snapshot.Metadata{Name: "clean"}, nil)
+			mockUI.EXPECT().Say(gomock.Any(), "clean", "clean")
+
+			Expect(snapshotCmd.Save("clean")).To(Succeed())
+			Expect(stopped).To(Equal(0))
+		})
+
+		It("starts the vm again when the save fails", func() {
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(true, nil)
+			mockUI.EXPECT().Say(gomock.Any()).AnyTimes()
+			mockUI.EXPECT().Say(gomock.Any(), gomock.Any()).AnyTimes()
+			mockHypervisor.EXPECT().Stop("cfdev")
+			mockStore.EXPECT().Save("clean", "/state", "/state/disk.qcow2").Return(snapshot.Metadata{}, errors.New("some-error"))
+
+			Expect(snapshotCmd.Save("clean")).To(MatchError("some-error"))
+			Expect(stopped).To(Equal(0))
+			Expect(resumed).To(Equal(1))
+		})
+
+		It("tells how to start the vm when it does not start again", func() {
+			resumeErr = errors.New("some-start-error")
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(true, nil)
+			mockUI.EXPECT().Say(gomock.Any()).AnyTimes()
+			mockUI.EXPECT().Say(gomock.Any(), gomock.Any()).AnyTimes()
+			mockHypervisor.EXPECT().Stop("cfdev")
+			mockStore.EXPECT().Save("clean", "/state", "/state/disk.qcow2").Return(snapshot.Metadata{}, errors.New("some-error"))
+
+			Expect(snapshotCmd.Save("clean")).To(MatchError(
+				"the VM is left stopped as it failed to start again (some-start-error), start it with 'cf dev start --resume': some-error",
+			))
+		})
+
+		It("refuses an environment that is not fully deployed", func() {
+			provisioned = false
+
+			Expect(snapshotCmd.Save("clean")).To(MatchError(ContainSubstring("CF Dev is not fully deployed")))
+		})
+	})
+
+	Describe("restore", func() {
+		It("starts cf dev from the snapshot", func() {
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(false, nil)
+
+			Expect(snapshotCmd.Restore("clean")).To(Succeed())
+			Expect(started).To(Equal([]string{"clean"}))
+		})
+
+		It("fails while cf dev is running", func() {
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(true, nil)
+
+			Expect(snapshotCmd.Restore("clean")).To(MatchError(ContainSubstring("stop it with 'cf dev stop'")))
+			Expect(started).To(BeEmpty())
+		})
+	})
+
+	Describe("list", func() {
+		It("prints the snapshots", func() {
+			createdAt := time.Date(2018, 6, 1, 12, 30, 0, 0, time.UTC)
+			mockStore.EXPECT().List().Return([]snapshot.Metadata{
+				{Name: "clean", CreatedAt: createdAt, DepsIsoPath: "/cache/cf-deps.iso"},
+			}, nil)
+			gomock.InOrder(
+				mockUI.EXPECT().Say("%-24s %-18s %s", "NAME", "CREATED", "DEPS ISO"),
+				mockUI.EXPECT().Say("%-24s %-18s %s", "clean", createdAt.Local().Format("2006-01-02 15:04"), "/cache/cf-deps.iso"),
+			)
+
+			Expect(snapshotCmd.List()).To(Succeed())
+		})
+
+		It("says when there are none", func() {
+			mockStore.EXPECT().List().Return([]snapshot.Metadata{}, nil)
+			mockUI.EXPECT().Say("No snapshots, save one with: cf dev snapshot save NAME")
+
+			Expect(snapshotCmd.List()).To(Succeed())
+		})
+	})
+})
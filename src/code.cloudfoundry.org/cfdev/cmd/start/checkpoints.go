@@ -0,0 +1,97 @@
+package start
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	phaseVMCreated    = "vm-created"
+	phaseGardenUp     = "garden-up"
+	phaseBoshDeployed = "bosh-deployed"
+	phaseCFDeployed   = "cf-deployed"
+	phaseAppProxySet  = "app-proxy-set"
+	phaseProvisioned  = "provisioned"
+)
+
+func servicePhase(name string) string {
+	return "service-deployed:" + name
+}
+
+// checkpoints records the completed phases of a start in the state dir so an
+// interrupted start can continue from the first incomplete phase.
+type checkpoints struct {
+	path         string
+	DepsIsoPath  string   `json:"deps_iso_path"`
+	SystemDomain string   `json:"system_domain,omitempty"`
+	Phases       []string `json:"phases"`
+	// Recover is set when the vm booted an existing disk, its deployments
+	// have to be recovered once the director is up
+	Recover bool `json:"recover,omitempty"`
+}
+
+func loadCheckpoints(stateDir string) *checkpoints {
+	c := &checkpoints{path: filepath.Join(stateDir, "checkpoints.json")}
+	if contents, err := ioutil.ReadFile(c.path); err == nil {
+		json.Unmarshal(contents, c)
+	}
+	return c
+}
+
+// Provisioned tells whether a start of the environment in the state dir ran
+// to the end
+func Provisioned(stateDir string) bool {
+	return loadCheckpoints(stateDir).done(phaseProvisioned)
+}
+
+func (c *checkpoints) resumable() bool {
+	return c.done(phaseVMCreated) && !c.done(phaseProvisioned)
+}
+
+func (c *checkpoints) done(phase string) bool {
+	for _, p := range c.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checkpoints) complete(phase string) error {
+	if c.done(phase) {
+		return nil
+	}
+	c.Phases = append(c.Phases, phase)
+	return c.save()
+}
+
+// rebooted keeps the deployment phases when the vm boots an existing disk,
+// i.e. a restored snapshot or a resumed start. The director runs in a
+// container which does not survive the restart, so it is deployed again and
+// recovers the deployments on its disk.
+func (c *checkpoints) rebooted() error {
+	if c.done(phaseBoshDeployed) {
+		c.Recover = true
+	}
+	var phases []string
+	for _, p := range c.Phases {
+		if p != phaseGardenUp && p != phaseBoshDeployed && p != phaseProvisioned {
+			phases = append(phases, p)
+		}
+	}
+	c.Phases = phases
+	return c.save()
+}
+
+func (c *checkpoints) save() error {
+	contents, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, contents, 0644)
+}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	host "code.cloudfoundry.org/cfdev/host"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -33,13 +34,26 @@ func (m *MockHost) EXPECT() *MockHostMockRecorder {
 }
 
 // CheckRequirements mocks base method
-func (m *MockHost) CheckRequirements() error {
-	ret := m.ctrl.Call(m, "CheckRequirements")
+func (m *MockHost) CheckRequirements(arg0 host.Requirements) error {
+	ret := m.ctrl.Call(m, "CheckRequirements", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CheckRequirements indicates an expected call of CheckRequirements
-func (mr *MockHostMockRecorder) CheckRequirements() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRequirements", reflect.TypeOf((*MockHost)(nil).CheckRequirements))
+func (mr *MockHostMockRecorder) CheckRequirements(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRequirements", reflect.TypeOf((*MockHost)(nil).CheckRequirements), arg0)
+}
+
+// TrustedCerts mocks base method
+func (m *MockHost) TrustedCerts() ([]byte, error) {
+	ret := m.ctrl.Call(m, "TrustedCerts")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrustedCerts indicates an expected call of TrustedCerts
+func (mr *MockHostMockRecorder) TrustedCerts() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedCerts", reflect.TypeOf((*MockHost)(nil).TrustedCerts))
 }
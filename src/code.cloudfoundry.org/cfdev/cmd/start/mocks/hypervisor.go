@@ -45,6 +45,18 @@ func (mr *MockHypervisorMockRecorder) CreateVM(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVM", reflect.TypeOf((*MockHypervisor)(nil).CreateVM), arg0)
 }
 
+// DiskPath mocks base method
+func (m *MockHypervisor) DiskPath(arg0 string) string {
+	ret := m.ctrl.Call(m, "DiskPath", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DiskPath indicates an expected call of DiskPath
+func (mr *MockHypervisorMockRecorder) DiskPath(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskPath", reflect.TypeOf((*MockHypervisor)(nil).DiskPath), arg0)
+}
+
 // IsRunning mocks base method
 func (m *MockHypervisor) IsRunning(arg0 string) (bool, error) {
 	ret := m.ctrl.Call(m, "IsRunning", arg0)
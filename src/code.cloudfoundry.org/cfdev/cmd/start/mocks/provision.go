@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	env "code.cloudfoundry.org/cfdev/env"
 	provision "code.cloudfoundry.org/cfdev/provision"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -46,27 +47,27 @@ func (mr *MockProvisionerMockRecorder) DeployBosh() *gomock.Call {
 }
 
 // DeployCloudFoundry mocks base method
-func (m *MockProvisioner) DeployCloudFoundry(arg0 []string) error {
-	ret := m.ctrl.Call(m, "DeployCloudFoundry", arg0)
+func (m *MockProvisioner) DeployCloudFoundry(arg0 []string, arg1 string) error {
+	ret := m.ctrl.Call(m, "DeployCloudFoundry", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeployCloudFoundry indicates an expected call of DeployCloudFoundry
-func (mr *MockProvisionerMockRecorder) DeployCloudFoundry(arg0 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployCloudFoundry", reflect.TypeOf((*MockProvisioner)(nil).DeployCloudFoundry), arg0)
+func (mr *MockProvisionerMockRecorder) DeployCloudFoundry(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployCloudFoundry", reflect.TypeOf((*MockProvisioner)(nil).DeployCloudFoundry), arg0, arg1)
 }
 
 // DeployServices mocks base method
-func (m *MockProvisioner) DeployServices(arg0 provision.UI, arg1 []provision.Service) error {
-	ret := m.ctrl.Call(m, "DeployServices", arg0, arg1)
+func (m *MockProvisioner) DeployServices(arg0 provision.UI, arg1 []provision.Service, arg2 func(provision.Service) error) error {
+	ret := m.ctrl.Call(m, "DeployServices", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeployServices indicates an expected call of DeployServices
-func (mr *MockProvisionerMockRecorder) DeployServices(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployServices", reflect.TypeOf((*MockProvisioner)(nil).DeployServices), arg0, arg1)
+func (mr *MockProvisionerMockRecorder) DeployServices(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployServices", reflect.TypeOf((*MockProvisioner)(nil).DeployServices), arg0, arg1, arg2)
 }
 
 // GetServices mocks base method
@@ -95,6 +96,18 @@ func (mr *MockProvisionerMockRecorder) Ping() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockProvisioner)(nil).Ping))
 }
 
+// RecoverDeployments mocks base method
+func (m *MockProvisioner) RecoverDeployments() error {
+	ret := m.ctrl.Call(m, "RecoverDeployments")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecoverDeployments indicates an expected call of RecoverDeployments
+func (mr *MockProvisionerMockRecorder) RecoverDeployments() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverDeployments", reflect.TypeOf((*MockProvisioner)(nil).RecoverDeployments))
+}
+
 // ReportProgress mocks base method
 func (m *MockProvisioner) ReportProgress(arg0 provision.UI, arg1 string) {
 	m.ctrl.Call(m, "ReportProgress", arg0, arg1)
@@ -104,3 +117,15 @@ func (m *MockProvisioner) ReportProgress(arg0 provision.UI, arg1 string) {
 func (mr *MockProvisionerMockRecorder) ReportProgress(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportProgress", reflect.TypeOf((*MockProvisioner)(nil).ReportProgress), arg0, arg1)
 }
+
+// SetProxyEnvironment mocks base method
+func (m *MockProvisioner) SetProxyEnvironment(arg0 env.ProxyConfig, arg1 string) error {
+	ret := m.ctrl.Call(m, "SetProxyEnvironment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProxyEnvironment indicates an expected call of SetProxyEnvironment
+func (mr *MockProvisionerMockRecorder) SetProxyEnvironment(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProxyEnvironment", reflect.TypeOf((*MockProvisioner)(nil).SetProxyEnvironment), arg0, arg1)
+}
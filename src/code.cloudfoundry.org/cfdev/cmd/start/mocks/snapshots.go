@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: code.cloudfoundry.org/cfdev/cmd/start (interfaces: Snapshots)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	snapshot "code.cloudfoundry.org/cfdev/snapshot"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockSnapshots is a mock of Snapshots interface
+type MockSnapshots struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotsMockRecorder
+}
+
+// MockSnapshotsMockRecorder is the mock recorder for MockSnapshots
+type MockSnapshotsMockRecorder struct {
+	mock *MockSnapshots
+}
+
+// NewMockSnapshots creates a new mock instance
+func NewMockSnapshots(ctrl *gomock.Controller) *MockSnapshots {
+	mock := &MockSnapshots{ctrl: ctrl}
+	mock.recorder = &MockSnapshotsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSnapshots) EXPECT() *MockSnapshotsMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method
+func (m *MockSnapshots) Get(arg0 string) (snapshot.Metadata, error) {
+	ret := m.ctrl.Call(m, "Get", arg0)
+	ret0, _ := ret[0].(snapshot.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockSnapshotsMockRecorder) Get(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSnapshots)(nil).Get), arg0)
+}
+
+// Restore mocks base method
+func (m *MockSnapshots) Restore(arg0 snapshot.Metadata, arg1, arg2 string) error {
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockSnapshotsMockRecorder) Restore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockSnapshots)(nil).Restore), arg0, arg1, arg2)
+}
+
+// Verify mocks base method
+func (m *MockSnapshots) Verify(arg0 snapshot.Metadata) error {
+	ret := m.ctrl.Call(m, "Verify", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify
+func (mr *MockSnapshotsMockRecorder) Verify(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSnapshots)(nil).Verify), arg0)
+}
@@ -33,15 +33,15 @@ func (m *MockVpnKit) EXPECT() *MockVpnKitMockRecorder {
 }
 
 // Start mocks base method
-func (m *MockVpnKit) Start() error {
-	ret := m.ctrl.Call(m, "Start")
+func (m *MockVpnKit) Start(arg0 string) error {
+	ret := m.ctrl.Call(m, "Start", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Start indicates an expected call of Start
-func (mr *MockVpnKitMockRecorder) Start() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockVpnKit)(nil).Start))
+func (mr *MockVpnKitMockRecorder) Start(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockVpnKit)(nil).Start), arg0)
 }
 
 // Stop mocks base method
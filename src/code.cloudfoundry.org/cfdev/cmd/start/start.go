@@ -15,6 +15,7 @@ import (
 	"code.cloudfoundry.org/cfdev/errors"
 	"code.cloudfoundry.org/cfdev/provision"
 	"code.cloudfoundry.org/cfdev/resource"
+	"code.cloudfoundry.org/cfdev/snapshot"
 	"github.com/spf13/cobra"
 
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 
 	"code.cloudfoundry.org/cfdev/cfanalytics"
 	"code.cloudfoundry.org/cfdev/env"
+	"code.cloudfoundry.org/cfdev/host"
 	"code.cloudfoundry.org/cfdev/hypervisor"
 )
 
@@ -50,7 +52,8 @@ type HostNet interface {
 
 //go:generate mockgen -package mocks -destination mocks/host.go code.cloudfoundry.org/cfdev/cmd/start Host
 type Host interface {
-	CheckRequirements() error
+	CheckRequirements(host.Requirements) error
+	TrustedCerts() ([]byte, error)
 }
 
 //go:generate mockgen -package mocks -destination mocks/cache.go code.cloudfoundry.org/cfdev/cmd/start Cache
@@ -65,7 +68,7 @@ type CFDevD interface {
 
 //go:generate mockgen -package mocks -destination mocks/vpnkit.go code.cloudfoundry.org/cfdev/cmd/start VpnKit
 type VpnKit interface {
-	Start() error
+	Start(systemDomain string) error
 	Stop() error
 	Watch(chan string)
 }
@@ -76,16 +79,19 @@ type Hypervisor interface {
 	Start(vmName string) error
 	Stop(vmName string) error
 	IsRunning(vmName string) (bool, error)
+	DiskPath(vmName string) string
 }
 
 //go:generate mockgen -package mocks -destination mocks/provision.go code.cloudfoundry.org/cfdev/cmd/start Provisioner
 type Provisioner interface {
 	Ping() error
 	DeployBosh() error
-	DeployCloudFoundry([]string) error
+	DeployCloudFoundry([]string, string) error
 	GetServices() ([]provision.Service, string, error)
-	DeployServices(provision.UI, []provision.Service) error
+	DeployServices(provision.UI, []provision.Service, func(provision.Service) error) error
 	ReportProgress(provision.UI, string)
+	SetProxyEnvironment(env.ProxyConfig, string) error
+	RecoverDeployments() error
 }
 
 //go:generate mockgen -package mocks -destination mocks/isoreader.go code.cloudfoundry.org/cfdev/cmd/start IsoReader
@@ -93,12 +99,36 @@ type IsoReader interface {
 	Read(isoPath string) (iso.Metadata, error)
 }
 
+//go:generate mockgen -package mocks -destination mocks/snapshots.go code.cloudfoundry.org/cfdev/cmd/start Snapshots
+type Snapshots interface {
+	Get(name string) (snapshot.Metadata, error)
+	Verify(snapshot.Metadata) error
+	Restore(meta snapshot.Metadata, stateDir, diskPath string) error
+}
+
 type Args struct {
 	Registries  string
 	DepsIsoPath string
 	NoProvision bool
 	Cpus        int
 	Mem         int
+	Domain      string
+	Services    string
+	Resume      bool
+	NoAppProxy  bool
+	OpsFiles    []string
+	VarsFiles   []string
+	Vars        []string
+
+	RegistryCAs    []string
+	RegistryMirror string
+
+	CACerts []string
+	// HostCACerts is nil unless the flag is passed
+	HostCACerts *bool
+
+	// Snapshot is set by cf dev snapshot restore
+	Snapshot string
 }
 
 type Start struct {
@@ -116,16 +146,21 @@ type Start struct {
 	VpnKit          VpnKit
 	Hypervisor      Hypervisor
 	Provisioner     Provisioner
+	Snapshots       Snapshots
 }
 
 const compatibilityVersion = "v1"
-const defaultMemory = 4192
+const vmDiskBytes = 80 << 30
 
 func (s *Start) Cmd() *cobra.Command {
 	args := Args{}
+	var hostCACerts bool
 	cmd := &cobra.Command{
 		Use: "start",
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cmd.Flags().Changed("host-ca-certs") {
+				args.HostCACerts = &hostCACerts
+			}
 			if err := s.Execute(args); err != nil {
 				return errors.SafeWrap(err, "cf dev start")
 			}
@@ -136,9 +171,20 @@ func (s *Start) Cmd() *cobra.Command {
 	pf := cmd.PersistentFlags()
 	pf.StringVarP(&args.DepsIsoPath, "file", "f", "", "path to .dev file containing bosh & cf bits")
 	pf.StringVarP(&args.Registries, "registries", "r", "", "docker registries that skip ssl validation - ie. host:port,host2:port2")
-	pf.IntVarP(&args.Cpus, "cpus", "c", 4, "cpus to allocate to vm")
+	pf.IntVarP(&args.Cpus, "cpus", "c", 0, fmt.Sprintf("cpus to allocate to vm (default %d)", config.DefaultCpus))
 	pf.IntVarP(&args.Mem, "memory", "m", 0, "memory to allocate to vm in MB")
+	pf.StringVar(&args.Domain, "domain", "", fmt.Sprintf("system domain of the CF deployment (default %s)", config.DefaultSystemDomain))
+	pf.StringVarP(&args.Services, "services", "s", "", "services to deploy - ie. mysql,redis, or 'all' or 'none' (default all)")
+	pf.StringArrayVar(&args.OpsFiles, "ops-file", nil, "ops file to apply to the cf deployment, or to another one with a prefix - ie. bosh:director-ops.yml or cf-mysql:mysql-ops.yml")
+	pf.StringArrayVar(&args.VarsFiles, "vars-file", nil, "file with variables for the deployments")
+	pf.StringArrayVar(&args.Vars, "var", nil, "variable for the deployments - ie. key=value")
+	pf.StringArrayVar(&args.RegistryCAs, "registry-ca", nil, "CA cert the cells trust for a docker registry - ie. host:port=ca.pem")
+	pf.StringVar(&args.RegistryMirror, "registry-mirror", "", "pull-through mirror the cells pull docker hub images from")
+	pf.StringArrayVar(&args.CACerts, "ca-cert", nil, "PEM file with CA certs the vm, bosh and cf trust - ie. for a corporate proxy")
+	pf.BoolVar(&hostCACerts, "host-ca-certs", false, "trust the CA certs added to the host trust store")
 	pf.BoolVarP(&args.NoProvision, "no-provision", "n", false, "start vm but do not provision")
+	pf.BoolVar(&args.NoAppProxy, "no-app-proxy", false, "do not set the host proxy in the env var groups of the apps")
+	pf.BoolVar(&args.Resume, "resume", false, "continue a previous start from its first incomplete phase, or boot the disk of a stopped vm")
 
 	pf.MarkHidden("no-provision")
 	return cmd
@@ -152,17 +198,57 @@ func (s *Start) Execute(args Args) error {
 		case name := <-s.LocalExit:
 			s.UI.Say("ERROR: %s has stopped", name)
 		}
-		s.Hypervisor.Stop("cfdev")
-		s.VpnKit.Stop()
+		s.Hypervisor.Stop(s.Config.VMName())
+		if s.VpnKit != nil {
+			s.VpnKit.Stop()
+		}
 		os.Exit(128)
 	}()
 
+	layers, err := s.layers(args)
+	if err != nil {
+		return err
+	}
+
+	var restored *snapshot.Metadata
+	if args.Snapshot != "" {
+		meta, err := s.Snapshots.Get(args.Snapshot)
+		if err != nil {
+			return err
+		}
+		if err := s.Snapshots.Verify(meta); err != nil {
+			return err
+		}
+		// the deployments on the disk of the snapshot use its system domain
+		layers = append([]config.Layer{{
+			Source: config.SourceSnapshot,
+			Values: config.StartFile{SystemDomain: meta.SystemDomain},
+		}}, layers...)
+		restored = &meta
+	}
+	settings := config.ResolveStartConfig(layers...)
+
+	overrides, err := provision.ParseOverrides(args.OpsFiles, args.VarsFiles, args.Vars)
+	if err != nil {
+		return err
+	}
+	if err := overrides.AddRegistrySettings(settings); err != nil {
+		return err
+	}
+	if err := s.addTrustedCerts(&overrides, settings); err != nil {
+		return err
+	}
+	proxy, err := env.BuildProxy(s.Config.BoshDirectorIP, s.Config.CFRouterIP, s.Config.HostIP, settings.SystemDomain)
+	if err != nil {
+		return err
+	}
+
 	depsIsoName := "cf"
 	depsIsoPath := filepath.Join(s.Config.CacheDir, "cf-deps.iso")
-	if args.DepsIsoPath != "" {
-		depsIsoName = filepath.Base(args.DepsIsoPath)
+	if settings.DepsFile != "" {
+		depsIsoName = filepath.Base(settings.DepsFile)
 		var err error
-		depsIsoPath, err = filepath.Abs(args.DepsIsoPath)
+		depsIsoPath, err = filepath.Abs(settings.DepsFile)
 		if err != nil {
 			return errors.SafeWrap(err, "determining absolute path to deps iso")
 		}
@@ -172,19 +258,43 @@ func (s *Start) Execute(args Args) error {
 
 		s.Config.Dependencies.Remove("cf-deps.iso")
 	}
+	if restored != nil {
+		depsIsoName, depsIsoPath = filepath.Base(restored.DepsIsoPath), restored.DepsIsoPath
+		s.Config.Dependencies.Remove("cf-deps.iso")
+	}
 
 	s.AnalyticsToggle.SetProp("type", depsIsoName)
 	s.Analytics.Event(cfanalytics.START_BEGIN)
-	if err := s.Host.CheckRequirements(); err != nil {
+	var appProxy *env.ProxyConfig
+	if proxy.HTTP != nil || proxy.HTTPS != nil {
+		s.UI.Say("Using proxy %s", proxy)
+		if !args.NoAppProxy {
+			proxyConfig := proxy.AppConfig()
+			appProxy = &proxyConfig
+		}
+	}
+	if err := s.Host.CheckRequirements(s.requirements(settings, proxy)); err != nil {
 		return err
 	}
 
-	if running, err := s.Hypervisor.IsRunning("cfdev"); err != nil {
+	cp := loadCheckpoints(s.Config.StateDir)
+	if running, err := s.Hypervisor.IsRunning(s.Config.VMName()); err != nil {
 		return errors.SafeWrap(err, "is running")
+	} else if running && restored != nil {
+		return fmt.Errorf("CF Dev is running, stop it with 'cf dev stop' before restoring a snapshot")
+	} else if running && args.Resume && cp.resumable() {
+		return s.resume(settings, cp, appProxy, false)
 	} else if running {
+		if cp.resumable() {
+			s.UI.Say("A previous start did not finish, run 'cf dev start --resume' to continue it")
+		}
 		s.UI.Say("CF Dev is already running...")
 		s.Analytics.Event(cfanalytics.START_END, map[string]interface{}{"alreadyrunning": true})
 		return nil
+	} else if args.Resume && restored == nil && cp.done(phaseVMCreated) && s.diskExists() {
+		return s.resume(settings, cp, appProxy, true)
+	} else if args.Resume {
+		s.UI.Say("There is no previous start to resume, starting from the beginning...")
 	}
 
 	if err := env.SetupHomeDir(s.Config); err != nil {
@@ -209,7 +319,7 @@ func (s *Start) Execute(args Args) error {
 		return errors.SafeWrap(err, "adding aliases")
 	}
 
-	registries, err := s.parseDockerRegistriesFlag(args.Registries)
+	registries, err := s.parseDockerRegistriesFlag(strings.Join(settings.Registries, ","))
 	if err != nil {
 		return errors.SafeWrap(err, "Unable to parse docker registries")
 	}
@@ -227,43 +337,74 @@ func (s *Start) Execute(args Args) error {
 		return fmt.Errorf("%s is not compatible with CF Dev. Please use a compatible file", depsIsoName)
 	}
 
-	if args.Mem <= 0 {
-		if isoConfig.DefaultMemory > 0 {
-			args.Mem = isoConfig.DefaultMemory
-		} else {
-			args.Mem = defaultMemory
-		}
+	deployments := provision.CustomDeployments(s.Config.StateDir)
+	for _, service := range isoConfig.Services {
+		deployments = append(deployments, service.Deployment)
+	}
+	if err := overrides.CheckDeployments(deployments); err != nil {
+		return err
+	}
+
+	settings = config.ResolveStartConfig(append(layers, config.Layer{
+		Source: config.SourceISO,
+		Values: config.StartFile{Memory: isoConfig.DefaultMemory},
+	})...)
+
+	services, err := provision.SelectServices(isoConfig.Services, settings.Services)
+	if err != nil {
+		return err
 	}
 
 	s.UI.Say("Creating the VM...")
 	if err := s.Hypervisor.CreateVM(hypervisor.VM{
-		Name:     "cfdev",
-		CPUs:     args.Cpus,
-		MemoryMB: args.Mem,
+		Name:     s.Config.VMName(),
+		CPUs:     settings.Cpus,
+		MemoryMB: settings.Memory,
 		DepsIso:  depsIsoPath,
 	}); err != nil {
 		return errors.SafeWrap(err, "creating the vm")
 	}
-	s.UI.Say("Starting VPNKit...")
-	if err := s.VpnKit.Start(); err != nil {
-		return errors.SafeWrap(err, "starting vpnkit")
+	if restored != nil {
+		if cp, services, err = s.restore(*restored, settings, isoConfig); err != nil {
+			return err
+		}
+	} else {
+		cp = &checkpoints{path: cp.path, DepsIsoPath: depsIsoPath, SystemDomain: settings.SystemDomain}
+		if err := s.checkpoint(cp, phaseVMCreated); err != nil {
+			return err
+		}
+		if err := provision.SaveSelection(s.Config.StateDir, services); err != nil {
+			return errors.SafeWrap(err, "failed to record the selected services")
+		}
+		if err := provision.SaveOverrides(s.Config.StateDir, overrides); err != nil {
+			return errors.SafeWrap(err, "failed to save the ops and vars files")
+		}
+	}
+	if s.VpnKit != nil {
+		s.UI.Say("Starting VPNKit...")
+		if err := s.VpnKit.Start(settings.SystemDomain); err != nil {
+			return errors.SafeWrap(err, "starting vpnkit")
+		}
+		s.VpnKit.Watch(s.LocalExit)
 	}
-	s.VpnKit.Watch(s.LocalExit)
 
 	s.UI.Say("Starting the VM...")
-	if err := s.Hypervisor.Start("cfdev"); err != nil {
+	if err := s.Hypervisor.Start(s.Config.VMName()); err != nil {
 		return errors.SafeWrap(err, "starting the vm")
 	}
 
 	s.UI.Say("Waiting for Garden...")
 	s.waitForGarden()
+	if err := s.checkpoint(cp, phaseGardenUp); err != nil {
+		return err
+	}
 
 	if args.NoProvision {
 		s.UI.Say("VM will not be provisioned because '-n' (no-provision) flag was specified.")
 		return nil
 	}
 
-	if err := s.provision(isoConfig, registries); err != nil {
+	if err := s.provision(isoConfig, services, settings, registries, appProxy, cp); err != nil {
 		return err
 	}
 
@@ -272,32 +413,303 @@ func (s *Start) Execute(args Args) error {
 	return nil
 }
 
-func (s *Start) provision(isoConfig iso.Metadata, registries []string) error {
-	s.UI.Say("Deploying the BOSH Director...")
-	if err := s.Provisioner.DeployBosh(); err != nil {
-		return errors.SafeWrap(err, "Failed to deploy the BOSH Director")
+// resume continues a start from its first incomplete phase. A stopped vm is
+// booted from its disk first, which takes down the director.
+func (s *Start) resume(settings config.StartConfig, cp *checkpoints, appProxy *env.ProxyConfig, boot bool) error {
+	s.UI.Say("Resuming the previous start of CF Dev...")
+	if cp.SystemDomain != "" {
+		settings.SystemDomain = cp.SystemDomain
+	}
+
+	registries, err := s.parseDockerRegistriesFlag(strings.Join(settings.Registries, ","))
+	if err != nil {
+		return errors.SafeWrap(err, "Unable to parse docker registries")
+	}
+
+	isoConfig, err := s.IsoReader.Read(cp.DepsIsoPath)
+	if err != nil {
+		return errors.SafeWrap(err, fmt.Sprintf("%s is not compatible with CF Dev. Please use a compatible file.", filepath.Base(cp.DepsIsoPath)))
+	}
+
+	if names, ok := provision.LoadSelection(s.Config.StateDir); ok {
+		settings.Services = names
+		if len(names) == 0 {
+			settings.Services = []string{provision.NoServices}
+		}
+	}
+	services, err := provision.SelectServices(isoConfig.Services, settings.Services)
+	if err != nil {
+		return err
+	}
+
+	if boot {
+		if err := s.boot(settings, cp); err != nil {
+			return err
+		}
+	} else if s.VpnKit != nil {
+		s.VpnKit.Watch(s.LocalExit)
+	}
+
+	s.UI.Say("Waiting for Garden...")
+	s.waitForGarden()
+	if err := s.checkpoint(cp, phaseGardenUp); err != nil {
+		return err
+	}
+
+	if err := s.provision(isoConfig, services, settings, registries, appProxy, cp); err != nil {
+		return err
+	}
+
+	s.Analytics.Event(cfanalytics.START_END, map[string]interface{}{"resumed": true})
+	return nil
+}
+
+func (s *Start) boot(settings config.StartConfig, cp *checkpoints) error {
+	if err := cp.rebooted(); err != nil {
+		return errors.SafeWrap(err, "failed to record start progress")
 	}
 
-	s.UI.Say("Deploying CF...")
-	s.Provisioner.ReportProgress(s.UI, "cf")
-	if err := s.Provisioner.DeployCloudFoundry(registries); err != nil {
-		return errors.SafeWrap(err, "Failed to deploy the Cloud Foundry")
+	if err := s.osSpecificSetup(); err != nil {
+		return err
+	}
+	if err := s.HostNet.AddLoopbackAliases(s.Config.BoshDirectorIP, s.Config.CFRouterIP); err != nil {
+		return errors.SafeWrap(err, "adding aliases")
 	}
 
-	if err := s.Provisioner.DeployServices(s.UI, isoConfig.Services); err != nil {
-		return errors.SafeWrap(err, "Failed to deploy services")
+	if s.VpnKit != nil {
+		s.UI.Say("Starting VPNKit...")
+		if err := s.VpnKit.Start(settings.SystemDomain); err != nil {
+			return errors.SafeWrap(err, "starting vpnkit")
+		}
+		s.VpnKit.Watch(s.LocalExit)
+	}
+
+	s.UI.Say("Starting the VM...")
+	if err := s.Hypervisor.Start(s.Config.VMName()); err != nil {
+		return errors.SafeWrap(err, "starting the vm")
+	}
+	return nil
+}
+
+func (s *Start) diskExists() bool {
+	_, err := os.Stat(s.Hypervisor.DiskPath(s.Config.VMName()))
+	return err == nil
+}
+
+func (s *Start) restore(meta snapshot.Metadata, settings config.StartConfig, isoConfig iso.Metadata) (*checkpoints, []provision.Service, error) {
+	s.UI.Say("Restoring snapshot %s...", meta.Name)
+	if err := s.Snapshots.Restore(meta, s.Config.StateDir, s.Hypervisor.DiskPath(s.Config.VMName())); err != nil {
+		return nil, nil, errors.SafeWrap(err, "restoring the snapshot")
+	}
+
+	cp := loadCheckpoints(s.Config.StateDir)
+	if err := cp.rebooted(); err != nil {
+		return nil, nil, errors.SafeWrap(err, "failed to record start progress")
+	}
+
+	if names, ok := provision.LoadSelection(s.Config.StateDir); ok {
+		settings.Services = names
+		if len(names) == 0 {
+			settings.Services = []string{provision.NoServices}
+		}
+	}
+	services, err := provision.SelectServices(isoConfig.Services, settings.Services)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cp, services, nil
+}
+
+func (s *Start) provision(isoConfig iso.Metadata, services []provision.Service, settings config.StartConfig, registries []string, appProxy *env.ProxyConfig, cp *checkpoints) error {
+	if !cp.done(phaseBoshDeployed) {
+		s.UI.Say("Deploying the BOSH Director...")
+		if err := s.Provisioner.DeployBosh(); err != nil {
+			return errors.SafeWrap(err, "Failed to deploy the BOSH Director")
+		}
+		if err := s.checkpoint(cp, phaseBoshDeployed); err != nil {
+			return err
+		}
+	}
+
+	if cp.Recover {
+		s.UI.Say("Recovering the deployments...")
+		if err := s.Provisioner.RecoverDeployments(); err != nil {
+			return errors.SafeWrap(err, "Failed to recover the deployments")
+		}
+		cp.Recover = false
+		if err := cp.save(); err != nil {
+			return errors.SafeWrap(err, "failed to record start progress")
+		}
+	}
+
+	if !cp.done(phaseCFDeployed) {
+		s.UI.Say("Deploying CF...")
+		s.Provisioner.ReportProgress(s.UI, "cf")
+		if err := s.Provisioner.DeployCloudFoundry(registries, settings.SystemDomain); err != nil {
+			return errors.SafeWrap(err, "Failed to deploy the Cloud Foundry")
+		}
+		if err := s.checkpoint(cp, phaseCFDeployed); err != nil {
+			return err
+		}
+	}
+
+	if appProxy != nil && !cp.done(phaseAppProxySet) {
+		s.UI.Say("Setting the proxy for apps...")
+		if err := s.Provisioner.SetProxyEnvironment(*appProxy, settings.SystemDomain); err != nil {
+			return errors.SafeWrap(err, "Failed to set the proxy for apps")
+		}
+		if err := s.checkpoint(cp, phaseAppProxySet); err != nil {
+			return err
+		}
+	}
+
+	var remaining []provision.Service
+	for _, service := range services {
+		if !cp.done(servicePhase(service.Name)) {
+			remaining = append(remaining, service)
+		}
+	}
+	if len(remaining) > 0 {
+		err := s.Provisioner.DeployServices(s.UI, remaining, func(service provision.Service) error {
+			return s.checkpoint(cp, servicePhase(service.Name))
+		})
+		if err != nil {
+			return errors.SafeWrap(err, "Failed to deploy services")
+		}
 	}
 
 	if isoConfig.Message != "" {
 		t := template.Must(template.New("message").Parse(isoConfig.Message))
-		err := t.Execute(s.UI.Writer(), map[string]string{"SYSTEM_DOMAIN": "dev.cfdev.sh"})
+		err := t.Execute(s.UI.Writer(), map[string]string{"SYSTEM_DOMAIN": settings.SystemDomain})
 		if err != nil {
 			return errors.SafeWrap(err, "Failed to print deps file provided message")
 		}
 	}
+	return s.checkpoint(cp, phaseProvisioned)
+}
+
+func (s *Start) checkpoint(cp *checkpoints, phase string) error {
+	if err := cp.complete(phase); err != nil {
+		return errors.SafeWrap(err, "failed to record start progress")
+	}
+	return nil
+}
+
+func (s *Start) layers(args Args) ([]config.Layer, error) {
+	flags := config.StartFile{
+		Cpus:         args.Cpus,
+		Memory:       args.Mem,
+		DepsFile:     args.DepsIsoPath,
+		SystemDomain: args.Domain,
+	}
+	if args.Registries != "" {
+		flags.Registries = strings.Split(args.Registries, ",")
+	}
+	if args.Services != "" {
+		flags.Services = strings.Split(args.Services, ",")
+	}
+	flags.HostCACerts = args.HostCACerts
+	for _, caCert := range args.CACerts {
+		path, err := filepath.Abs(caCert)
+		if err != nil {
+			return nil, err
+		}
+		flags.CACerts = append(flags.CACerts, path)
+	}
+	if err := registryFlags(&flags, args); err != nil {
+		return nil, err
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return nil, errors.SafeWrap(err, "determining the project directory")
+	}
+	layers, err := config.StartLayers(s.Config.CFDevHome, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return append([]config.Layer{{Source: config.SourceFlag, Values: flags}}, layers...), nil
+}
+
+func registryFlags(flags *config.StartFile, args Args) error {
+	flags.RegistryMirror = args.RegistryMirror
+
+	setting := func(value, flag string) (string, string, config.RegistrySettings, error) {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", config.RegistrySettings{}, fmt.Errorf("invalid --%s '%s'", flag, value)
+		}
+		if flags.RegistrySettings == nil {
+			flags.RegistrySettings = map[string]config.RegistrySettings{}
+		}
+		return parts[0], parts[1], flags.RegistrySettings[parts[0]], nil
+	}
+
+	for _, value := range args.RegistryCAs {
+		host, path, settings, err := setting(value, "registry-ca")
+		if err != nil {
+			return err
+		}
+		if settings.CACert, err = filepath.Abs(path); err != nil {
+			return err
+		}
+		flags.RegistrySettings[host] = settings
+	}
+	return nil
+}
+
+func (s *Start) addTrustedCerts(overrides *provision.Overrides, settings config.StartConfig) error {
+	for _, path := range settings.CACerts {
+		certs, err := provision.ReadCACerts(path)
+		if err != nil {
+			return err
+		}
+		overrides.AddTrustedCerts(certs...)
+	}
+
+	if settings.TrustHostCACerts() {
+		contents, err := s.Host.TrustedCerts()
+		if err != nil {
+			return errors.SafeWrap(err, "failed to read the host trust store")
+		}
+		overrides.AddTrustedCerts(provision.SplitCerts(contents)...)
+	}
 	return nil
 }
 
+func (s *Start) requirements(settings config.StartConfig, proxy env.Proxy) host.Requirements {
+	var missingBytes uint64
+	for _, item := range s.Config.Dependencies.Items {
+		if info, err := os.Stat(filepath.Join(s.Config.CacheDir, item.Name)); err == nil && uint64(info.Size()) == item.Size {
+			continue
+		}
+		missingBytes += item.Size
+	}
+
+	return host.Requirements{
+		VM:          true,
+		CPUs:        settings.Cpus,
+		MemoryMB:    settings.Memory,
+		CacheDir:    s.Config.CacheDir,
+		CacheBytes:  missingBytes,
+		StateDir:    s.Config.StateDir,
+		VMDiskBytes: vmDiskBytes,
+		Networks:    host.VMNetworks(s.Config.BoshDirectorIP, s.Config.CFRouterIP, s.Config.ContainerNetwork),
+		Proxies:     proxyRequirements(proxy),
+	}
+}
+
+func proxyRequirements(proxy env.Proxy) []host.Proxy {
+	var proxies []host.Proxy
+	if proxy.HTTP != nil {
+		proxies = append(proxies, host.Proxy{Name: "HTTP proxy", URL: env.Redact(proxy.HTTP), Address: env.ProxyAddress(proxy.HTTP)})
+	}
+	if proxy.HTTPS != nil {
+		proxies = append(proxies, host.Proxy{Name: "HTTPS proxy", URL: env.Redact(proxy.HTTPS), Address: env.ProxyAddress(proxy.HTTPS)})
+	}
+	return proxies
+}
+
 func (s *Start) waitForGarden() {
 	for {
 		if err := s.Provisioner.Ping(); err == nil {
@@ -0,0 +1,5 @@
+package start
+
+func (s *Start) osSpecificSetup() error {
+	return nil
+}
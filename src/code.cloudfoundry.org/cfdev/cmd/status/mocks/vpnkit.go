@@ -0,0 +1,46 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: code.cloudfoundry.org/cfdev/cmd/status (interfaces: VpnKit)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockVpnKit is a mock of VpnKit interface
+type MockVpnKit struct {
+	ctrl     *gomock.Controller
+	recorder *MockVpnKitMockRecorder
+}
+
+// MockVpnKitMockRecorder is the mock recorder for MockVpnKit
+type MockVpnKitMockRecorder struct {
+	mock *MockVpnKit
+}
+
+// NewMockVpnKit creates a new mock instance
+func NewMockVpnKit(ctrl *gomock.Controller) *MockVpnKit {
+	mock := &MockVpnKit{ctrl: ctrl}
+	mock.recorder = &MockVpnKitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockVpnKit) EXPECT() *MockVpnKitMockRecorder {
+	return m.recorder
+}
+
+// IsRunning mocks base method
+func (m *MockVpnKit) IsRunning() (bool, error) {
+	ret := m.ctrl.Call(m, "IsRunning")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRunning indicates an expected call of IsRunning
+func (mr *MockVpnKitMockRecorder) IsRunning() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockVpnKit)(nil).IsRunning))
+}
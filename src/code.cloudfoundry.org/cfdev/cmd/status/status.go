@@ -0,0 +1,269 @@
+package status
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/provision"
+	"github.com/spf13/cobra"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/cmd/status UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+//go:generate mockgen -package mocks -destination mocks/hypervisor.go code.cloudfoundry.org/cfdev/cmd/status Hypervisor
+type Hypervisor interface {
+	IsRunning(vmName string) (bool, error)
+}
+
+//go:generate mockgen -package mocks -destination mocks/vpnkit.go code.cloudfoundry.org/cfdev/cmd/status VpnKit
+type VpnKit interface {
+	IsRunning() (bool, error)
+}
+
+//go:generate mockgen -package mocks -destination mocks/provision.go code.cloudfoundry.org/cfdev/cmd/status Provisioner
+type Provisioner interface {
+	Ping() error
+	Deployments() ([]bosh.DeploymentStatus, error)
+	GetServices() ([]provision.Service, string, error)
+}
+
+type Status struct {
+	UI           UI
+	Hypervisor   Hypervisor
+	VpnKit       VpnKit
+	Provisioner  Provisioner
+	HttpDo       func(req *http.Request) (*http.Response, error)
+	SystemDomain string
+	VMName       string
+	StateDir     string
+}
+
+type Args struct {
+	JSON bool
+}
+
+type Check struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message"`
+}
+
+type BoshReport struct {
+	Check
+	Deployments []bosh.DeploymentStatus `json:"deployments"`
+}
+
+type ServiceReport struct {
+	Name       string `json:"name"`
+	Deployment string `json:"deployment"`
+	Deployed   bool   `json:"deployed"`
+	Selected   bool   `json:"selected"`
+}
+
+type Report struct {
+	Healthy  bool            `json:"healthy"`
+	VM       Check           `json:"vm"`
+	VpnKit   Check           `json:"vpnkit"`
+	Garden   Check           `json:"garden"`
+	Bosh     BoshReport      `json:"bosh"`
+	CFAPI    Check           `json:"cf_api"`
+	Services []ServiceReport `json:"services"`
+}
+
+const vmName = "cfdev"
+
+func (s *Status) vmName() string {
+	if s.VMName == "" {
+		return vmName
+	}
+	return s.VMName
+}
+
+func (s *Status) Cmd() *cobra.Command {
+	args := Args{}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report the health of the running CF Dev environment",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return s.Execute(args)
+		},
+	}
+	cmd.PersistentFlags().BoolVar(&args.JSON, "json", false, "print the status as json")
+	return cmd
+}
+
+func (s *Status) Execute(args Args) error {
+	report := s.Report()
+
+	if args.JSON {
+		bytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.SafeWrap(err, "unable to marshal status")
+		}
+		s.UI.Say("%s", string(bytes))
+		return nil
+	}
+
+	s.print(report)
+	return nil
+}
+
+func (s *Status) Report() Report {
+	report := Report{Services: []ServiceReport{}}
+	report.Bosh.Deployments = []bosh.DeploymentStatus{}
+
+	report.VM = isRunning(s.Hypervisor.IsRunning(s.vmName()))
+	if s.VpnKit != nil {
+		report.VpnKit = isRunning(s.VpnKit.IsRunning())
+	} else {
+		report.VpnKit = Check{Healthy: true, Message: "not used"}
+	}
+	if !report.VM.Healthy {
+		report.Garden = Check{Message: "vm is not running"}
+		report.Bosh.Check = Check{Message: "vm is not running"}
+		report.CFAPI = Check{Message: "vm is not running"}
+		return report
+	}
+
+	report.Garden = reachable(s.Provisioner.Ping())
+	if deployments, err := s.Provisioner.Deployments(); err != nil {
+		report.Bosh.Check = reachable(err)
+	} else {
+		report.Bosh.Check = reachable(nil)
+		report.Bosh.Deployments = deployments
+	}
+	report.CFAPI = s.checkCFAPI()
+
+	if report.Garden.Healthy {
+		if services, _, err := s.Provisioner.GetServices(); err == nil {
+			selection, recorded := provision.LoadSelection(s.StateDir)
+			for _, service := range services {
+				report.Services = append(report.Services, ServiceReport{
+					Name:       service.Name,
+					Deployment: service.Deployment,
+					Deployed:   isDeployed(service.Deployment, report.Bosh.Deployments),
+					Selected:   !recorded || isSelected(service.Name, selection),
+				})
+			}
+		}
+	}
+
+	report.Healthy = report.VM.Healthy &&
+		report.VpnKit.Healthy &&
+		report.Garden.Healthy &&
+		report.Bosh.Healthy &&
+		report.CFAPI.Healthy
+	return report
+}
+
+func (s *Status) checkCFAPI() Check {
+	url := fmt.Sprintf("https://api.%s/v2/info", s.SystemDomain)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return Check{Message: err.Error()}
+	}
+
+	resp, err := s.httpDo(req)
+	if err != nil {
+		return Check{Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Check{Message: fmt.Sprintf("%s returned %s", url, resp.Status)}
+	}
+	return Check{Healthy: true, Message: fmt.Sprintf("reachable (%s)", url)}
+}
+
+func (s *Status) httpDo(req *http.Request) (*http.Response, error) {
+	if s.HttpDo != nil {
+		return s.HttpDo(req)
+	}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// the router presents a certificate signed by the deployment's own CA
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	return client.Do(req)
+}
+
+func (s *Status) print(report Report) {
+	s.UI.Say("VM:       %s", report.VM.Message)
+	s.UI.Say("VPNKit:   %s", report.VpnKit.Message)
+	s.UI.Say("Garden:   %s", report.Garden.Message)
+	s.UI.Say("BOSH:     %s", report.Bosh.Message)
+	for _, deployment := range report.Bosh.Deployments {
+		running := 0
+		for _, vm := range deployment.VMs {
+			if vm.ProcessState == "running" {
+				running++
+			}
+		}
+		s.UI.Say("  %s: %d of %d vms running", deployment.Name, running, len(deployment.VMs))
+		for _, vm := range deployment.VMs {
+			if vm.ProcessState != "running" {
+				s.UI.Say("    %s: %s", vm.Instance, vm.ProcessState)
+			}
+		}
+	}
+	s.UI.Say("CF API:   %s", report.CFAPI.Message)
+
+	if len(report.Services) > 0 {
+		s.UI.Say("Services:")
+		for _, service := range report.Services {
+			if service.Deployed {
+				s.UI.Say("  %s: deployed", service.Name)
+			} else if service.Selected {
+				s.UI.Say("  %s: not deployed", service.Name)
+			} else {
+				s.UI.Say("  %s: not selected", service.Name)
+			}
+		}
+	}
+}
+
+func isRunning(running bool, err error) Check {
+	switch {
+	case err != nil:
+		return Check{Message: err.Error()}
+	case running:
+		return Check{Healthy: true, Message: "running"}
+	default:
+		return Check{Message: "not running"}
+	}
+}
+
+func reachable(err error) Check {
+	if err != nil {
+		return Check{Message: fmt.Sprintf("unreachable (%s)", err)}
+	}
+	return Check{Healthy: true, Message: "reachable"}
+}
+
+func isDeployed(deployment string, deployments []bosh.DeploymentStatus) bool {
+	for _, d := range deployments {
+		if d.Name == deployment {
+			return true
+		}
+	}
+	return false
+}
+
+func isSelected(name string, selection []string) bool {
+	for _, selected := range selection {
+		if strings.EqualFold(selected, name) {
+			return true
+		}
+	}
+	return false
+}
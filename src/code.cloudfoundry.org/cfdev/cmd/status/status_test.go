@@ -0,0 +1,208 @@
+package status_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/cmd/status"
+	"code.cloudfoundry.org/cfdev/cmd/status/mocks"
+	"code.cloudfoundry.org/cfdev/provision"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status", func() {
+	var (
+		mockController  *gomock.Controller
+		mockUI          *mocks.MockUI
+		mockHypervisor  *mocks.MockHypervisor
+		mockVpnKit      *mocks.MockVpnKit
+		mockProvisioner *mocks.MockProvisioner
+		requestedURL    string
+		apiStatus       int
+		subject         *status.Status
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockUI = mocks.NewMockUI(mockController)
+		mockHypervisor = mocks.NewMockHypervisor(mockController)
+		mockVpnKit = mocks.NewMockVpnKit(mockController)
+		mockProvisioner = mocks.NewMockProvisioner(mockController)
+		apiStatus = http.StatusOK
+
+		subject = &status.Status{
+			UI:          mockUI,
+			Hypervisor:  mockHypervisor,
+			VpnKit:      mockVpnKit,
+			Provisioner: mockProvisioner,
+			HttpDo: func(req *http.Request) (*http.Response, error) {
+				requestedURL = req.URL.String()
+				return &http.Response{
+					StatusCode: apiStatus,
+					Status:     http.StatusText(apiStatus),
+					Body:       ioutil.NopCloser(strings.NewReader("{}")),
+				}, nil
+			},
+			SystemDomain: "dev.cfdev.sh",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	Context("when everything is running", func() {
+		BeforeEach(func() {
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(true, nil)
+			mockVpnKit.EXPECT().IsRunning().Return(true, nil)
+			mockProvisioner.EXPECT().Ping().Return(nil)
+			mockProvisioner.EXPECT().Deployments().Return([]bosh.DeploymentStatus{
+				{Name: "cf", VMs: []bosh.VMStatus{
+					{Instance: "router/0", ProcessState: "running"},
+					{Instance: "api/0", ProcessState: "failing"},
+				}},
+				{Name: "cf-mysql", VMs: []bosh.VMStatus{}},
+			}, nil)
+			mockProvisioner.EXPECT().GetServices().Return([]provision.Service{
+				{Name: "Mysql", Deployment: "cf-mysql"},
+				{Name: "RabbitMQ", Deployment: "cf-rabbitmq"},
+			}, "", nil)
+		})
+
+		It("reports a healthy environment", func() {
+			report := subject.Report()
+
+			Expect(report.Healthy).To(BeTrue())
+			Expect(requestedURL).To(Equal("https://api.dev.cfdev.sh/v2/info"))
+			Expect(report.Bosh.Deployments).To(HaveLen(2))
+			Expect(report.Services).To(Equal([]status.ServiceReport{
+				{Name: "Mysql", Deployment: "cf-mysql", Deployed: true, Selected: true},
+				{Name: "RabbitMQ", Deployment: "cf-rabbitmq", Deployed: false, Selected: true},
+			}))
+		})
+
+		Context("when start recorded a service selection", func() {
+			var stateDir string
+
+			BeforeEach(func() {
+				var err error
+				stateDir, err = ioutil.TempDir("", "state-dir")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(provision.SaveSelection(stateDir, []provision.Service{{Name: "Mysql"}})).To(Succeed())
+				subject.StateDir = stateDir
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(stateDir)
+			})
+
+			It("reports the services that were not selected", func() {
+				gomock.InOrder(
+					mockUI.EXPECT().Say("VM:       %s", "running"),
+					mockUI.EXPECT().Say("VPNKit:   %s", "running"),
+					mockUI.EXPECT().Say("Garden:   %s", "reachable"),
+					mockUI.EXPECT().Say("BOSH:     %s", "reachable"),
+					mockUI.EXPECT().Say("  %s: %d of %d vms running", "cf", 1, 2),
+					mockUI.EXPECT().Say("    %s: %s", "api/0", "failing"),
+					mockUI.EXPECT().Say("  %s: %d of %d vms running", "cf-mysql", 0, 0),
+					mockUI.EXPECT().Say("CF API:   %s", "reachable (https://api.dev.cfdev.sh/v2/info)"),
+					mockUI.EXPECT().Say("Services:"),
+					mockUI.EXPECT().Say("  %s: deployed", "Mysql"),
+					mockUI.EXPECT().Say("  %s: not selected", "RabbitMQ"),
+				)
+
+				Expect(subject.Execute(status.Args{})).To(Succeed())
+			})
+		})
+
+		It("prints a human readable report", func() {
+			gomock.InOrder(
+				mockUI.EXPECT().Say("VM:       %s", "running"),
+				mockUI.EXPECT().Say("VPNKit:   %s", "running"),
+				mockUI.EXPECT().Say("Garden:   %s", "reachable"),
+				mockUI.EXPECT().Say("BOSH:     %s", "reachable"),
+				mockUI.EXPECT().Say("  %s: %d of %d vms running", "cf", 1, 2),
+				mockUI.EXPECT().Say("    %s: %s", "api/0", "failing"),
+				mockUI.EXPECT().Say("  %s: %d of %d vms running", "cf-mysql", 0, 0),
+				mockUI.EXPECT().Say("CF API:   %s", "reachable (https://api.dev.cfdev.sh/v2/info)"),
+				mockUI.EXPECT().Say("Services:"),
+				mockUI.EXPECT().Say("  %s: deployed", "Mysql"),
+				mockUI.EXPECT().Say("  %s: not deployed", "RabbitMQ"),
+			)
+
+			Expect(subject.Execute(status.Args{})).To(Succeed())
+		})
+
+		It("prints the report as json", func() {
+			var output string
+			mockUI.EXPECT().Say("%s", gomock.Any()).Do(func(_ string, args ...interface{}) {
+				output = args[0].(string)
+			})
+
+			Expect(subject.Execute(status.Args{JSON: true})).To(Succeed())
+
+			var report map[string]interface{}
+			Expect(json.Unmarshal([]byte(output), &report)).To(Succeed())
+			Expect(report["healthy"]).To(BeTrue())
+			Expect(report["bosh"]).To(HaveKeyWithValue("healthy", true))
+			Expect(report["bosh"]).To(HaveKey("deployments"))
+			Expect(report["cf_api"]).To(HaveKeyWithValue("healthy", true))
+		})
+	})
+
+	Context("when the vm is not running", func() {
+		BeforeEach(func() {
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(false, nil)
+			mockVpnKit.EXPECT().IsRunning().Return(false, nil)
+		})
+
+		It("skips the checks that need the vm", func() {
+			report := subject.Report()
+
+			Expect(report.Healthy).To(BeFalse())
+			Expect(report.VM).To(Equal(status.Check{Message: "not running"}))
+			Expect(report.Garden).To(Equal(status.Check{Message: "vm is not running"}))
+			Expect(report.Services).To(BeEmpty())
+		})
+	})
+
+	Context("when the vm does not use vpnkit", func() {
+		BeforeEach(func() {
+			subject.VpnKit = nil
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(false, nil)
+		})
+
+		It("does not check vpnkit", func() {
+			report := subject.Report()
+
+			Expect(report.VpnKit).To(Equal(status.Check{Healthy: true, Message: "not used"}))
+		})
+	})
+
+	Context("when the BOSH director and CF API are unreachable", func() {
+		BeforeEach(func() {
+			apiStatus = http.StatusBadGateway
+			mockHypervisor.EXPECT().IsRunning("cfdev").Return(true, nil)
+			mockVpnKit.EXPECT().IsRunning().Return(true, nil)
+			mockProvisioner.EXPECT().Ping().Return(nil)
+			mockProvisioner.EXPECT().Deployments().Return(nil, errors.New("connection refused"))
+			mockProvisioner.EXPECT().GetServices().Return(nil, "", nil)
+		})
+
+		It("reports an unhealthy environment", func() {
+			report := subject.Report()
+
+			Expect(report.Healthy).To(BeFalse())
+			Expect(report.Bosh.Check).To(Equal(status.Check{Message: "unreachable (connection refused)"}))
+			Expect(report.CFAPI.Healthy).To(BeFalse())
+			Expect(report.CFAPI.Message).To(ContainSubstring("Bad Gateway"))
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"code.cloudfoundry.org/cfdev/cfanalytics"
 	"code.cloudfoundry.org/cfdev/config"
 	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/host"
 	"github.com/spf13/cobra"
 )
 
@@ -31,7 +32,7 @@ type HostNet interface {
 
 //go:generate mockgen -package mocks -destination mocks/host.go code.cloudfoundry.org/cfdev/cmd/stop Host
 type Host interface {
-	CheckRequirements() error
+	CheckRequirements(host.Requirements) error
 }
 
 //go:generate mockgen -package mocks -destination mocks/linuxkit.go code.cloudfoundry.org/cfdev/cmd/stop Hypervisor
@@ -54,6 +55,9 @@ type Stop struct {
 	Analytics    Analytics
 	HostNet      HostNet
 	Host         Host
+	// Running reports whether the vm of an environment is running, so that
+	// the network helper shared by all environments is kept while in use
+	Running func(config.Config) (bool, error)
 }
 
 func (s *Stop) Cmd() *cobra.Command {
@@ -63,22 +67,20 @@ func (s *Stop) Cmd() *cobra.Command {
 	}
 }
 
-const vmName = "cfdev"
-
 func (s *Stop) RunE(cmd *cobra.Command, args []string) error {
 	s.Analytics.Event(cfanalytics.STOP)
 
-	if err := s.Host.CheckRequirements(); err != nil {
+	if err := s.Host.CheckRequirements(host.Requirements{}); err != nil {
 		return err
 	}
 
 	var reterr error
 
-	if err := s.Hypervisor.Stop(vmName); err != nil {
+	if err := s.Hypervisor.Stop(s.Config.VMName()); err != nil {
 		reterr = errors.SafeWrap(err, "failed to stop the VM")
 	}
 
-	if err := s.Hypervisor.Destroy(vmName); err != nil {
+	if err := s.Hypervisor.Destroy(s.Config.VMName()); err != nil {
 		reterr = errors.SafeWrap(err, "failed to destroy the VM")
 	}
 
@@ -94,7 +96,7 @@ func (s *Stop) RunE(cmd *cobra.Command, args []string) error {
 		reterr = errors.SafeWrap(err, "failed to remove IP aliases")
 	}
 
-	if runtime.GOOS == "darwin" {
+	if runtime.GOOS == "darwin" && !s.otherEnvRunning() {
 		if _, err := s.CfdevdClient.Uninstall(); err != nil {
 			reterr = errors.SafeWrap(err, "failed to uninstall cfdevd")
 		}
@@ -106,3 +108,22 @@ func (s *Stop) RunE(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+func (s *Stop) otherEnvRunning() bool {
+	if s.Running == nil {
+		return false
+	}
+	envs, err := s.Config.Environments()
+	if err != nil {
+		return false
+	}
+	for _, env := range envs {
+		if env.Env == s.Config.Env {
+			continue
+		}
+		if running, err := s.Running(env); err == nil && running {
+			return true
+		}
+	}
+	return false
+}
@@ -11,6 +11,7 @@ import (
 	"code.cloudfoundry.org/cfdev/cmd/stop"
 	"code.cloudfoundry.org/cfdev/cmd/stop/mocks"
 	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/host"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -71,7 +72,7 @@ var _ = Describe("Stop", func() {
 
 	It("destroys the VM, uninstalls vpnkit and cfdevd, tears down aliases, and sends analytics event", func() {
 		mockAnalytics.EXPECT().Event(cfanalytics.STOP)
-		mockHost.EXPECT().CheckRequirements()
+		mockHost.EXPECT().CheckRequirements(host.Requirements{})
 		mockHypervisor.EXPECT().Stop("cfdev")
 		mockHypervisor.EXPECT().Destroy("cfdev")
 		mockVpnkit.EXPECT().Stop()
@@ -88,7 +89,7 @@ var _ = Describe("Stop", func() {
 	Context("stopping the VM fails", func() {
 		It("stops the others and returns VM error", func() {
 			mockAnalytics.EXPECT().Event(cfanalytics.STOP)
-			mockHost.EXPECT().CheckRequirements()
+			mockHost.EXPECT().CheckRequirements(host.Requirements{})
 			mockHypervisor.EXPECT().Stop("cfdev").Return(errors.New("test"))
 			mockHypervisor.EXPECT().Destroy("cfdev")
 			mockVpnkit.EXPECT().Stop()
@@ -106,7 +107,7 @@ var _ = Describe("Stop", func() {
 	Context("destroying the VM fails", func() {
 		It("stops the others and returns VM error", func() {
 			mockAnalytics.EXPECT().Event(cfanalytics.STOP)
-			mockHost.EXPECT().CheckRequirements()
+			mockHost.EXPECT().CheckRequirements(host.Requirements{})
 			mockHypervisor.EXPECT().Stop("cfdev")
 			mockHypervisor.EXPECT().Destroy("cfdev").Return(errors.New("test"))
 			mockVpnkit.EXPECT().Stop()
@@ -124,7 +125,7 @@ var _ = Describe("Stop", func() {
 	Context("stopping vpnkit fails", func() {
 		It("stops the others and returns vpnkit error", func() {
 			mockAnalytics.EXPECT().Event(cfanalytics.STOP)
-			mockHost.EXPECT().CheckRequirements()
+			mockHost.EXPECT().CheckRequirements(host.Requirements{})
 			mockHypervisor.EXPECT().Stop("cfdev")
 			mockHypervisor.EXPECT().Destroy("cfdev")
 			mockVpnkit.EXPECT().Stop().Return(errors.New("test"))
@@ -142,7 +143,7 @@ var _ = Describe("Stop", func() {
 	Context("destroying vpnkit fails", func() {
 		It("stops the others and returns vpnkit error", func() {
 			mockAnalytics.EXPECT().Event(cfanalytics.STOP)
-			mockHost.EXPECT().CheckRequirements()
+			mockHost.EXPECT().CheckRequirements(host.Requirements{})
 			mockHypervisor.EXPECT().Stop("cfdev")
 			mockHypervisor.EXPECT().Destroy("cfdev")
 			mockVpnkit.EXPECT().Stop()
@@ -160,7 +161,7 @@ var _ = Describe("Stop", func() {
 	Context("removing aliases fails", func() {
 		It("stops the others and returns alias error", func() {
 			mockAnalytics.EXPECT().Event(cfanalytics.STOP)
-			mockHost.EXPECT().CheckRequirements()
+			mockHost.EXPECT().CheckRequirements(host.Requirements{})
 			mockHypervisor.EXPECT().Stop("cfdev")
 			mockHypervisor.EXPECT().Destroy("cfdev")
 			mockVpnkit.EXPECT().Stop()
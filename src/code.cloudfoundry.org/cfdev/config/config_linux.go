@@ -0,0 +1,142 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"code.cloudfoundry.org/cfdev/errors"
+
+	"code.cloudfoundry.org/cfdev/resource"
+	"code.cloudfoundry.org/cfdev/semver"
+)
+
+// NamedEnvs is true as qemu forwards the garden port and the addresses of
+// every environment to its own vm.
+const NamedEnvs = true
+
+// the user networking of qemu (slirp) answers for the host and its dns
+// resolver on these addresses
+const (
+	DefaultHostIP = "10.0.2.2"
+	DefaultDNSIP  = "10.0.2.3"
+)
+
+var (
+	cfdepsUrl  string
+	cfdepsMd5  string
+	cfdepsSize string
+
+	cfdevefiUrl  string
+	cfdevefiMd5  string
+	cfdevefiSize string
+
+	cliVersion   string
+	analyticsKey string
+)
+
+type Config struct {
+	BoshDirectorIP         string
+	CFRouterIP             string
+	HostIP                 string
+	ContainerNetwork       string
+	CFDevHome              string
+	Env                    string
+	EnvDir                 string
+	Slot                   int
+	GardenPort             int
+	StateDir               string
+	CacheDir               string
+	VpnKitStateDir         string
+	Dependencies           resource.Catalog
+	CFDevDSocketPath       string
+	CFDevDInstallationPath string
+	CliVersion             *semver.Version
+	AnalyticsKey           string
+}
+
+func NewConfig() (Config, error) {
+	cfdevHome := getCfdevHome()
+
+	catalog, err := catalog()
+	if err != nil {
+		return Config{}, err
+	}
+
+	conf := Config{
+		BoshDirectorIP:         DefaultBoshDirectorIP,
+		CFRouterIP:             DefaultCFRouterIP,
+		HostIP:                 DefaultHostIP,
+		ContainerNetwork:       DefaultContainerNetwork,
+		CFDevHome:              cfdevHome,
+		EnvDir:                 cfdevHome,
+		GardenPort:             DefaultGardenPort,
+		StateDir:               filepath.Join(cfdevHome, "state", "qemu"),
+		CacheDir:               filepath.Join(cfdevHome, "cache"),
+		VpnKitStateDir:         filepath.Join(cfdevHome, "state", "vpnkit"),
+		Dependencies:           catalog,
+		CFDevDSocketPath:       filepath.Join("/var", "run", "cfdevd.socket"),
+		CFDevDInstallationPath: filepath.Join("/usr", "local", "libexec", "org.cloudfoundry.cfdevd"),
+		CliVersion:             semver.Must(semver.New(cliVersion)),
+		AnalyticsKey:           analyticsKey,
+	}
+
+	if err := conf.applyNetwork(); err != nil {
+		return Config{}, err
+	}
+	return conf, nil
+}
+
+func aToUint64(a string) uint64 {
+	i, err := strconv.ParseUint(a, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+func catalog() (resource.Catalog, error) {
+	override := os.Getenv("CFDEV_CATALOG")
+
+	if override != "" {
+		var c resource.Catalog
+		if err := json.Unmarshal([]byte(override), &c); err != nil {
+			return resource.Catalog{}, errors.SafeWrap(err, "Unable to parse CFDEV_CATALOG env variable")
+		}
+		return c, nil
+	}
+
+	catalog := resource.Catalog{
+		Items: []resource.Item{
+			{
+				URL:   cfdepsUrl,
+				Name:  "cf-deps.iso",
+				MD5:   cfdepsMd5,
+				Size:  aToUint64(cfdepsSize),
+				InUse: true,
+			},
+			{
+				URL:   cfdevefiUrl,
+				Name:  "cfdev-efi.iso",
+				MD5:   cfdevefiMd5,
+				Size:  aToUint64(cfdevefiSize),
+				InUse: true,
+			},
+		},
+	}
+	sort.Slice(catalog.Items, func(i, j int) bool {
+		return catalog.Items[i].Size < catalog.Items[j].Size
+	})
+	return catalog, nil
+}
+
+func getCfdevHome() string {
+	cfdevHome := os.Getenv("CFDEV_HOME")
+	if cfdevHome != "" {
+		return cfdevHome
+	}
+
+	return filepath.Join(os.Getenv("HOME"), ".cfdev")
+}
@@ -14,6 +14,17 @@ import (
 	"runtime"
 )
 
+// NamedEnvs is false as the vm image exposes garden, the director and the
+// router on fixed ports and addresses through vpnkit, which every
+// environment would compete for.
+const NamedEnvs = false
+
+// vpnkit answers for the host and its dns resolver on these addresses
+const (
+	DefaultHostIP = "192.168.65.2"
+	DefaultDNSIP  = "192.168.65.1"
+)
+
 var (
 	cfdepsUrl  string
 	cfdepsMd5  string
@@ -59,7 +70,12 @@ type Config struct {
 	BoshDirectorIP         string
 	CFRouterIP             string
 	HostIP                 string
+	ContainerNetwork       string
 	CFDevHome              string
+	Env                    string
+	EnvDir                 string
+	Slot                   int
+	GardenPort             int
 	StateDir               string
 	CacheDir               string
 	VpnKitStateDir         string
@@ -78,11 +94,14 @@ func NewConfig() (Config, error) {
 		return Config{}, err
 	}
 
-	return Config{
-		BoshDirectorIP:         "10.245.0.2",
-		CFRouterIP:             "10.144.0.34",
-		HostIP:					"192.168.65.2",
+	conf := Config{
+		BoshDirectorIP:         DefaultBoshDirectorIP,
+		CFRouterIP:             DefaultCFRouterIP,
+		HostIP:					DefaultHostIP,
+		ContainerNetwork:       DefaultContainerNetwork,
 		CFDevHome:              cfdevHome,
+		EnvDir:                 cfdevHome,
+		GardenPort:             DefaultGardenPort,
 		StateDir:               filepath.Join(cfdevHome, "state", "linuxkit"),
 		CacheDir:               filepath.Join(cfdevHome, "cache"),
 		VpnKitStateDir:         filepath.Join(cfdevHome, "state", "vpnkit"),
@@ -91,7 +110,12 @@ func NewConfig() (Config, error) {
 		CFDevDInstallationPath: filepath.Join("/Library", "PrivilegedHelperTools", "org.cloudfoundry.cfdevd"),
 		CliVersion:             semver.Must(semver.New(cliVersion)),
 		AnalyticsKey:           analyticsKey,
-	}, nil
+	}
+
+	if err := conf.applyNetwork(); err != nil {
+		return Config{}, err
+	}
+	return conf, nil
 }
 
 func aToUint64(a string) uint64 {
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/errors"
+)
+
+const (
+	DefaultEnv        = "default"
+	DefaultGardenPort = 8888
+
+	// maxSlot keeps the offset default addresses within their /24 blocks,
+	// e.g. 10.144.255.34 is the router of the last slot
+	maxSlot = 255
+
+	envsDir  = "envs"
+	slotFile = "slot"
+)
+
+var envNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// VMName names the vm of the environment; the default environment keeps the
+// name it had before environments existed.
+func (c Config) VMName() string {
+	if c.Env == "" {
+		return "cfdev"
+	}
+	return "cfdev-" + c.Env
+}
+
+func (c Config) EnvName() string {
+	if c.Env == "" {
+		return DefaultEnv
+	}
+	return c.Env
+}
+
+// Label namespaces a daemon label to the environment
+func (c Config) Label(label string) string {
+	if c.Env == "" {
+		return label
+	}
+	return label + "." + c.Env
+}
+
+func (c Config) GardenAddr() string {
+	port := c.GardenPort
+	if port == 0 {
+		port = DefaultGardenPort
+	}
+	return fmt.Sprintf("localhost:%d", port)
+}
+
+// ForEnv returns the config of the named environment. Every named
+// environment gets its own state directories under CFDevHome and a slot
+// number which offsets its garden port and its default IP aliases, so that
+// it can run next to the others.
+func (c Config) ForEnv(name string) (Config, error) {
+	hypervisorDir := filepath.Base(c.StateDir)
+
+	if name == "" || name == DefaultEnv {
+		c.Env, c.Slot, c.EnvDir = "", 0, c.CFDevHome
+	} else if !envNamePattern.MatchString(name) {
+		return Config{}, fmt.Errorf("invalid environment name '%s': use lowercase letters, digits and dashes", name)
+	} else {
+		slot, err := envSlot(c.CFDevHome, name)
+		if err != nil {
+			return Config{}, err
+		}
+		c.Env, c.Slot, c.EnvDir = name, slot, filepath.Join(c.CFDevHome, envsDir, name)
+	}
+
+	c.StateDir = filepath.Join(c.EnvDir, "state", hypervisorDir)
+	c.VpnKitStateDir = filepath.Join(c.EnvDir, "state", "vpnkit")
+	c.GardenPort = DefaultGardenPort + c.Slot
+
+	if err := c.applyNetwork(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// SaveEnv records the slot of a named environment so that it keeps its
+// addresses and shows up in the environment list.
+func (c Config) SaveEnv() error {
+	if c.Env == "" {
+		return nil
+	}
+	if err := os.MkdirAll(c.EnvDir, 0755); err != nil {
+		return errors.SafeWrap(err, "failed to create environment dir")
+	}
+	if err := ioutil.WriteFile(filepath.Join(c.EnvDir, slotFile), []byte(strconv.Itoa(c.Slot)), 0644); err != nil {
+		return errors.SafeWrap(err, "failed to save environment")
+	}
+	return nil
+}
+
+// Environments returns the config of the default environment followed by
+// the saved named environments.
+func (c Config) Environments() ([]Config, error) {
+	def, err := c.ForEnv(DefaultEnv)
+	if err != nil {
+		return nil, err
+	}
+	envs := []Config{def}
+
+	slots, err := envSlots(c.CFDevHome)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedNames(slots) {
+		env, err := c.ForEnv(name)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+// AliasIPs returns the loopback aliases of every environment, which the
+// shared network helper has to allow.
+func (c Config) AliasIPs() []string {
+	ips := []string{c.BoshDirectorIP, c.CFRouterIP}
+	if envs, err := c.Environments(); err == nil {
+		for _, env := range envs {
+			ips = append(ips, env.BoshDirectorIP, env.CFRouterIP)
+		}
+	}
+
+	var unique []string
+	seen := map[string]bool{}
+	for _, ip := range ips {
+		if ip != "" && !seen[ip] {
+			seen[ip] = true
+			unique = append(unique, ip)
+		}
+	}
+	return unique
+}
+
+func envSlot(cfdevHome, name string) (int, error) {
+	slots, err := envSlots(cfdevHome)
+	if err != nil {
+		return 0, err
+	}
+	if slot, ok := slots[name]; ok {
+		return slot, nil
+	}
+
+	used := map[int]bool{}
+	for _, slot := range slots {
+		used[slot] = true
+	}
+	slot := 1
+	for used[slot] {
+		slot++
+	}
+	if slot > maxSlot {
+		return 0, fmt.Errorf("unable to create environment '%s': there are already %d environments in %s", name, maxSlot, filepath.Join(cfdevHome, envsDir))
+	}
+	return slot, nil
+}
+
+func envSlots(cfdevHome string) (map[string]int, error) {
+	slots := map[string]int{}
+
+	dirs, err := ioutil.ReadDir(filepath.Join(cfdevHome, envsDir))
+	if os.IsNotExist(err) {
+		return slots, nil
+	} else if err != nil {
+		return nil, errors.SafeWrap(err, "failed to read environments")
+	}
+
+	for _, dir := range dirs {
+		contents, err := ioutil.ReadFile(filepath.Join(cfdevHome, envsDir, dir.Name(), slotFile))
+		if err != nil {
+			continue
+		}
+		slot, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+		if err != nil || slot < 1 || slot > maxSlot {
+			continue
+		}
+		slots[dir.Name()] = slot
+	}
+	return slots, nil
+}
+
+func sortedNames(slots map[string]int) []string {
+	var names []string
+	for name := range slots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// offsetIP moves a default address into the /24 of the environment's slot,
+// e.g. 10.144.0.34 becomes 10.144.2.34 in slot 2.
+func offsetIP(ip string, slot int) string {
+	addr := net.ParseIP(ip).To4()
+	if addr == nil || slot == 0 {
+		return ip
+	}
+	addr[2] = byte(int(addr[2]) + slot)
+	return addr.String()
+}
@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"code.cloudfoundry.org/cfdev/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("environments", func() {
+	var (
+		homeDir string
+		base    config.Config
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = ioutil.TempDir("", "cfdev-home")
+		Expect(err).NotTo(HaveOccurred())
+
+		base = config.Config{
+			CFDevHome:  homeDir,
+			EnvDir:     homeDir,
+			StateDir:   filepath.Join(homeDir, "state", "qemu"),
+			GardenPort: config.DefaultGardenPort,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(homeDir)
+		os.Unsetenv("CFDEV_ROUTER_IP")
+	})
+
+	Describe("ForEnv", func() {
+		It("keeps the names and addresses of the default environment", func() {
+			c, err := base.ForEnv(config.DefaultEnv)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.EnvName()).To(Equal("default"))
+			Expect(c.VMName()).To(Equal("cfdev"))
+			Expect(c.Label("org.cloudfoundry.cfdev.vpnkit")).To(Equal("org.cloudfoundry.cfdev.vpnkit"))
+			Expect(c.GardenAddr()).To(Equal("localhost:8888"))
+			Expect(c.StateDir).To(Equal(filepath.Join(homeDir, "state", "qemu")))
+			Expect(c.VpnKitStateDir).To(Equal(filepath.Join(homeDir, "state", "vpnkit")))
+			Expect(c.BoshDirectorIP).To(Equal("10.245.0.2"))
+			Expect(c.CFRouterIP).To(Equal("10.144.0.34"))
+		})
+
+		It("namespaces a named environment", func() {
+			c, err := base.ForEnv("stable")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.EnvName()).To(Equal("stable"))
+			Expect(c.VMName()).To(Equal("cfdev-stable"))
+			Expect(c.Label("org.cloudfoundry.cfdev.vpnkit")).To(Equal("org.cloudfoundry.cfdev.vpnkit.stable"))
+			Expect(c.GardenAddr()).To(Equal("localhost:8889"))
+			Expect(c.StateDir).To(Equal(filepath.Join(homeDir, "envs", "stable", "state", "qemu")))
+			Expect(c.VpnKitStateDir).To(Equal(filepath.Join(homeDir, "envs", "stable", "state", "vpnkit")))
+			Expect(c.BoshDirectorIP).To(Equal("10.245.1.2"))
+			Expect(c.CFRouterIP).To(Equal("10.144.1.34"))
+		})
+
+		It("gives every saved environment its own slot", func() {
+			stable, err := base.ForEnv("stable")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stable.SaveEnv()).To(Succeed())
+
+			branch, err := stable.ForEnv("branch")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(branch.Slot).To(Equal(2))
+			Expect(branch.GardenAddr()).To(Equal("localhost:8890"))
+
+			again, err := branch.ForEnv("stable")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again.Slot).To(Equal(1))
+		})
+
+		It("does not move addresses that are configured", func() {
+			os.Setenv("CFDEV_ROUTER_IP", "10.150.0.34")
+
+			c, err := base.ForEnv("stable")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.CFRouterIP).To(Equal("10.150.0.34"))
+			Expect(c.BoshDirectorIP).To(Equal("10.245.1.2"))
+		})
+
+		It("derives the cf static ranges from the router of the slot", func() {
+			for slot := 1; slot <= 5; slot++ {
+				Expect(os.MkdirAll(filepath.Join(homeDir, "envs", fmt.Sprintf("env-%d", slot)), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(homeDir, "envs", fmt.Sprintf("env-%d", slot), "slot"), []byte(strconv.Itoa(slot)), 0644)).To(Succeed())
+			}
+
+			c, err := base.ForEnv("env-5")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.BoshDirectorIP).To(Equal("10.245.5.2"))
+			Expect(c.CFRouterIP).To(Equal("10.144.5.34"))
+
+			network, err := config.BuildNetwork(c.BoshDirectorIP, c.CFRouterIP, c.ContainerNetwork)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(network.DirectorCIDR).To(Equal("10.245.5.0/24"))
+			Expect(network.CFStatic[0]).To(Equal("10.144.5.0 - 10.144.5.127"))
+		})
+
+		It("fails when every slot is taken", func() {
+			for slot := 1; slot <= 255; slot++ {
+				Expect(os.MkdirAll(filepath.Join(homeDir, "envs", fmt.Sprintf("env-%d", slot)), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(homeDir, "envs", fmt.Sprintf("env-%d", slot), "slot"), []byte(strconv.Itoa(slot)), 0644)).To(Succeed())
+			}
+
+			_, err := base.ForEnv("one-more")
+			Expect(err).To(MatchError(ContainSubstring("there are already 255 environments")))
+		})
+
+		It("rejects invalid names", func() {
+			_, err := base.ForEnv("Not Valid")
+			Expect(err).To(MatchError(ContainSubstring("invalid environment name 'Not Valid'")))
+		})
+	})
+
+	Describe("Environments", func() {
+		It("lists the default environment followed by the saved ones", func() {
+			for _, name := range []string{"stable", "branch"} {
+				c, err := base.ForEnv(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.SaveEnv()).To(Succeed())
+			}
+
+			envs, err := base.Environments()
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, env := range envs {
+				names = append(names, env.EnvName())
+			}
+			Expect(names).To(Equal([]string{"default", "branch", "stable"}))
+			Expect(base.AliasIPs()).To(ConsistOf("10.245.0.2", "10.144.0.34", "10.245.1.2", "10.144.1.34", "10.245.2.2", "10.144.2.34"))
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"net"
+)
+
+// staticBlocks is the number of /24 blocks, starting at the router's, whose
+// lower half the cloud config reserves for static IPs
+const staticBlocks = 4
+
+// Network holds the subnets the director and the cf deployment are rendered
+// with, derived from the configured addresses
+type Network struct {
+	DirectorIP      string
+	DirectorCIDR    string
+	DirectorGateway string
+
+	CFSubnet  string
+	CFGateway string
+	CFStatic  []string
+
+	ContainerNetwork string
+}
+
+// BuildNetwork puts the director on the /24 of its address and cf on the /16
+// of the router address, e.g. 10.245.0.2 is on 10.245.0.0/24 behind
+// 10.245.0.1 and 10.144.0.34 is on 10.144.0.0/16 behind 10.144.0.1.
+func BuildNetwork(directorIP, routerIP, containerNetwork string) (Network, error) {
+	director := net.ParseIP(directorIP).To4()
+	if director == nil {
+		return Network{}, fmt.Errorf("bosh_director_ip must be an IPv4 address: %s", directorIP)
+	}
+	router := net.ParseIP(routerIP).To4()
+	if router == nil {
+		return Network{}, fmt.Errorf("router_ip must be an IPv4 address: %s", routerIP)
+	}
+	_, containers, err := net.ParseCIDR(containerNetwork)
+	if err != nil {
+		return Network{}, fmt.Errorf("container_network must be a CIDR: %s", containerNetwork)
+	}
+
+	directorNet := &net.IPNet{IP: director.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}
+	if director[3] < 2 || director[3] == 255 {
+		return Network{}, fmt.Errorf("bosh_director_ip %s must not be the network, gateway or broadcast address of %s", directorIP, directorNet)
+	}
+
+	cfNet := &net.IPNet{IP: router.Mask(net.CIDRMask(16, 32)), Mask: net.CIDRMask(16, 32)}
+	if router[3] > 127 || (router[2] == 0 && router[3] < 2) {
+		return Network{}, fmt.Errorf("router_ip %s must be between .2 and .127 of its /24, the static range of %s", routerIP, cfNet)
+	}
+
+	switch {
+	case overlaps(directorNet, cfNet):
+		return Network{}, fmt.Errorf("bosh_director_ip %s must not be in the cf subnet %s of router_ip %s", directorIP, cfNet, routerIP)
+	case overlaps(containers, directorNet):
+		return Network{}, fmt.Errorf("container_network %s overlaps the director subnet %s", containers, directorNet)
+	case overlaps(containers, cfNet):
+		return Network{}, fmt.Errorf("container_network %s overlaps the cf subnet %s", containers, cfNet)
+	}
+
+	n := Network{
+		DirectorIP:       director.String(),
+		DirectorCIDR:     directorNet.String(),
+		DirectorGateway:  nextIP(directorNet.IP, 1).String(),
+		CFSubnet:         cfNet.String(),
+		CFGateway:        nextIP(cfNet.IP, 1).String(),
+		ContainerNetwork: containers.String(),
+	}
+	for block := int(router[2]); block < int(router[2])+staticBlocks && block <= 255; block++ {
+		first := net.IPv4(router[0], router[1], byte(block), 0).To4()
+		if block == 0 {
+			// .0 is the network and .1 the gateway
+			first = nextIP(first, 2)
+		}
+		last := net.IPv4(router[0], router[1], byte(block), 127)
+		n.CFStatic = append(n.CFStatic, fmt.Sprintf("%s - %s", first, last))
+	}
+	return n, nil
+}
+
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func nextIP(ip net.IP, n int) net.IP {
+	next := make(net.IP, len(ip.To4()))
+	copy(next, ip.To4())
+	next[3] += byte(n)
+	return next
+}
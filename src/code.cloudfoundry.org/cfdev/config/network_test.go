@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"code.cloudfoundry.org/cfdev/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildNetwork", func() {
+	It("derives the default subnets", func() {
+		network, err := config.BuildNetwork("10.245.0.2", "10.144.0.34", "10.246.0.0/16")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(network).To(Equal(config.Network{
+			DirectorIP:      "10.245.0.2",
+			DirectorCIDR:    "10.245.0.0/24",
+			DirectorGateway: "10.245.0.1",
+			CFSubnet:        "10.144.0.0/16",
+			CFGateway:       "10.144.0.1",
+			CFStatic: []string{
+				"10.144.0.2 - 10.144.0.127",
+				"10.144.1.0 - 10.144.1.127",
+				"10.144.2.0 - 10.144.2.127",
+				"10.144.3.0 - 10.144.3.127",
+			},
+			ContainerNetwork: "10.246.0.0/16",
+		}))
+	})
+
+	It("keeps the static range inside the cf subnet", func() {
+		network, err := config.BuildNetwork("10.245.0.2", "10.144.254.34", "10.246.0.0/16")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(network.CFStatic).To(Equal([]string{
+			"10.144.254.0 - 10.144.254.127",
+			"10.144.255.0 - 10.144.255.127",
+		}))
+	})
+
+	It("rejects a router outside the static range", func() {
+		_, err := config.BuildNetwork("10.245.0.2", "10.144.0.200", "10.246.0.0/16")
+		Expect(err).To(MatchError(ContainSubstring("router_ip 10.144.0.200 must be between .2 and .127")))
+	})
+
+	It("rejects overlapping networks", func() {
+		_, err := config.BuildNetwork("10.245.0.2", "10.144.0.34", "10.0.0.0/8")
+		Expect(err).To(MatchError("container_network 10.0.0.0/8 overlaps the director subnet 10.245.0.0/24"))
+	})
+})
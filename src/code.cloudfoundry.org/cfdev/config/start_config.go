@@ -0,0 +1,368 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	DefaultCpus         = 4
+	DefaultMemory       = 4192
+	DefaultSystemDomain = "dev.cfdev.sh"
+
+	DefaultBoshDirectorIP   = "10.245.0.2"
+	DefaultCFRouterIP       = "10.144.0.34"
+	DefaultContainerNetwork = "10.246.0.0/16"
+
+	UserConfigFile    = "config.yml"
+	ProjectConfigFile = "cfdev.yml"
+)
+
+const (
+	SourceSnapshot = "snapshot"
+	SourceFlag     = "flag"
+	SourceISO      = "iso metadata"
+	SourceDefault  = "default"
+)
+
+var StartConfigKeys = []string{
+	"cpus",
+	"memory",
+	"registries",
+	"deps_file",
+	"services",
+	"system_domain",
+	"bosh_director_ip",
+	"router_ip",
+	"host_ip",
+	"container_network",
+	"registry_settings",
+	"registry_mirror",
+	"ca_certs",
+	"host_ca_certs",
+}
+
+// RegistrySettings configure how the cells reach a private docker registry.
+// Credentials are not kept, Diego takes them per push.
+type RegistrySettings struct {
+	CACert string `yaml:"ca_cert,omitempty"`
+}
+
+// StartFile holds the start settings a single source can provide; zero
+// values mean the source does not set them. Booleans are pointers so that a
+// source can set them to false.
+type StartFile struct {
+	Cpus         int      `yaml:"cpus,omitempty"`
+	Memory       int      `yaml:"memory,omitempty"`
+	Registries   []string `yaml:"registries,omitempty"`
+	DepsFile     string   `yaml:"deps_file,omitempty"`
+	Services     []string `yaml:"services,omitempty"`
+	SystemDomain string   `yaml:"system_domain,omitempty"`
+
+	BoshDirectorIP   string `yaml:"bosh_director_ip,omitempty"`
+	CFRouterIP       string `yaml:"router_ip,omitempty"`
+	HostIP           string `yaml:"host_ip,omitempty"`
+	ContainerNetwork string `yaml:"container_network,omitempty"`
+
+	RegistrySettings map[string]RegistrySettings `yaml:"registry_settings,omitempty"`
+	RegistryMirror   string                      `yaml:"registry_mirror,omitempty"`
+
+	CACerts     []string `yaml:"ca_certs,omitempty"`
+	HostCACerts *bool    `yaml:"host_ca_certs,omitempty"`
+}
+
+type Layer struct {
+	Source string
+	Values StartFile
+}
+
+type StartConfig struct {
+	StartFile
+	Sources map[string]string
+}
+
+func (c StartConfig) Value(key string) string {
+	switch key {
+	case "cpus":
+		return strconv.Itoa(c.Cpus)
+	case "memory":
+		return strconv.Itoa(c.Memory)
+	case "registries":
+		return strings.Join(c.Registries, ",")
+	case "deps_file":
+		return c.DepsFile
+	case "services":
+		return strings.Join(c.Services, ",")
+	case "system_domain":
+		return c.SystemDomain
+	case "bosh_director_ip":
+		return c.BoshDirectorIP
+	case "router_ip":
+		return c.CFRouterIP
+	case "host_ip":
+		return c.HostIP
+	case "container_network":
+		return c.ContainerNetwork
+	case "registry_settings":
+		return strings.Join(c.RegistryHosts(), ",")
+	case "registry_mirror":
+		return c.RegistryMirror
+	case "ca_certs":
+		return strings.Join(c.CACerts, ",")
+	case "host_ca_certs":
+		return strconv.FormatBool(c.TrustHostCACerts())
+	}
+	return ""
+}
+
+// StartLayers returns the env, project file and user file layers in order of
+// precedence.
+func StartLayers(cfdevHome, projectDir string) ([]Layer, error) {
+	env, err := envLayer()
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := fileLayer("project file", filepath.Join(projectDir, ProjectConfigFile))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := fileLayer("user file", filepath.Join(cfdevHome, UserConfigFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return []Layer{env, project, user}, nil
+}
+
+// ResolveStartConfig picks every setting from the first layer that sets it,
+// falling back to the built-in defaults.
+func ResolveStartConfig(layers ...Layer) StartConfig {
+	layers = append(layers, Layer{
+		Source: SourceDefault,
+		Values: StartFile{
+			Cpus:         DefaultCpus,
+			Memory:       DefaultMemory,
+			SystemDomain: DefaultSystemDomain,
+
+			BoshDirectorIP:   DefaultBoshDirectorIP,
+			CFRouterIP:       DefaultCFRouterIP,
+			HostIP:           DefaultHostIP,
+			ContainerNetwork: DefaultContainerNetwork,
+
+			HostCACerts: new(bool),
+		},
+	})
+
+	c := StartConfig{Sources: map[string]string{}}
+	for _, key := range StartConfigKeys {
+		c.Sources[key] = SourceDefault
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		source, v := layers[i].Source, layers[i].Values
+		if v.Cpus > 0 {
+			c.Cpus, c.Sources["cpus"] = v.Cpus, source
+		}
+		if v.Memory > 0 {
+			c.Memory, c.Sources["memory"] = v.Memory, source
+		}
+		if len(v.Registries) > 0 {
+			c.Registries, c.Sources["registries"] = v.Registries, source
+		}
+		if v.DepsFile != "" {
+			c.DepsFile, c.Sources["deps_file"] = v.DepsFile, source
+		}
+		if len(v.Services) > 0 {
+			c.Services, c.Sources["services"] = v.Services, source
+		}
+		if v.SystemDomain != "" {
+			c.SystemDomain, c.Sources["system_domain"] = v.SystemDomain, source
+		}
+		if v.BoshDirectorIP != "" {
+			c.BoshDirectorIP, c.Sources["bosh_director_ip"] = v.BoshDirectorIP, source
+		}
+		if v.CFRouterIP != "" {
+			c.CFRouterIP, c.Sources["router_ip"] = v.CFRouterIP, source
+		}
+		if v.HostIP != "" {
+			c.HostIP, c.Sources["host_ip"] = v.HostIP, source
+		}
+		if v.ContainerNetwork != "" {
+			c.ContainerNetwork, c.Sources["container_network"] = v.ContainerNetwork, source
+		}
+		if len(v.RegistrySettings) > 0 {
+			c.RegistrySettings, c.Sources["registry_settings"] = v.RegistrySettings, source
+		}
+		if v.RegistryMirror != "" {
+			c.RegistryMirror, c.Sources["registry_mirror"] = v.RegistryMirror, source
+		}
+		if len(v.CACerts) > 0 {
+			c.CACerts, c.Sources["ca_certs"] = v.CACerts, source
+		}
+		if v.HostCACerts != nil {
+			c.HostCACerts, c.Sources["host_ca_certs"] = v.HostCACerts, source
+		}
+	}
+	return c
+}
+
+func (c StartConfig) TrustHostCACerts() bool {
+	return c.HostCACerts != nil && *c.HostCACerts
+}
+
+// RegistryHosts returns the registries with settings in a stable order
+func (c StartConfig) RegistryHosts() []string {
+	var hosts []string
+	for host := range c.RegistrySettings {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// SystemDomain returns the system domain set through the env or the config
+// files, for commands that do not take start flags.
+func (c Config) SystemDomain() string {
+	projectDir, _ := os.Getwd()
+	layers, err := StartLayers(c.CFDevHome, projectDir)
+	if err != nil {
+		return DefaultSystemDomain
+	}
+	return ResolveStartConfig(layers...).SystemDomain
+}
+
+// applyNetwork replaces the network addresses with the ones set through the
+// env or the config files, for hosts where the defaults clash with a VPN.
+func (c *Config) applyNetwork() error {
+	projectDir, _ := os.Getwd()
+	layers, err := StartLayers(c.CFDevHome, projectDir)
+	if err != nil {
+		return err
+	}
+	settings := ResolveStartConfig(layers...)
+	if settings.Sources["bosh_director_ip"] == SourceDefault {
+		settings.BoshDirectorIP = offsetIP(settings.BoshDirectorIP, c.Slot)
+	}
+	if settings.Sources["router_ip"] == SourceDefault {
+		settings.CFRouterIP = offsetIP(settings.CFRouterIP, c.Slot)
+	}
+
+	for _, key := range []string{"bosh_director_ip", "router_ip", "host_ip"} {
+		if ip := settings.Value(key); net.ParseIP(ip).To4() == nil {
+			return fmt.Errorf("%s must be an IPv4 address: %s (%s)", key, ip, settings.Sources[key])
+		}
+	}
+	if _, _, err := net.ParseCIDR(settings.ContainerNetwork); err != nil {
+		return fmt.Errorf("container_network must be a CIDR: %s (%s)", settings.ContainerNetwork, settings.Sources["container_network"])
+	}
+	if _, err := BuildNetwork(settings.BoshDirectorIP, settings.CFRouterIP, settings.ContainerNetwork); err != nil {
+		return err
+	}
+
+	c.BoshDirectorIP = settings.BoshDirectorIP
+	c.CFRouterIP = settings.CFRouterIP
+	c.HostIP = settings.HostIP
+	c.ContainerNetwork = settings.ContainerNetwork
+	return nil
+}
+
+func envLayer() (Layer, error) {
+	var v StartFile
+	var err error
+
+	if v.Cpus, err = envInt("CFDEV_CPUS"); err != nil {
+		return Layer{}, err
+	}
+	if v.Memory, err = envInt("CFDEV_MEMORY"); err != nil {
+		return Layer{}, err
+	}
+	v.Registries = envList("CFDEV_REGISTRIES")
+	v.DepsFile = os.Getenv("CFDEV_DEPS_FILE")
+	v.Services = envList("CFDEV_SERVICES")
+	v.SystemDomain = os.Getenv("CFDEV_SYSTEM_DOMAIN")
+	v.BoshDirectorIP = os.Getenv("CFDEV_BOSH_DIRECTOR_IP")
+	v.CFRouterIP = os.Getenv("CFDEV_ROUTER_IP")
+	v.HostIP = os.Getenv("CFDEV_HOST_IP")
+	v.ContainerNetwork = os.Getenv("CFDEV_CONTAINER_NETWORK")
+	v.RegistryMirror = os.Getenv("CFDEV_REGISTRY_MIRROR")
+	v.CACerts = envList("CFDEV_CA_CERTS")
+	if v.HostCACerts, err = envBool("CFDEV_HOST_CA_CERTS"); err != nil {
+		return Layer{}, err
+	}
+
+	return Layer{Source: "env", Values: v}, nil
+}
+
+func envInt(name string) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number: %s", name, value)
+	}
+	return i, nil
+}
+
+func envBool(name string) (*bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false: %s", name, value)
+	}
+	return &b, nil
+}
+
+func envList(name string) []string {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func fileLayer(source, path string) (Layer, error) {
+	layer := Layer{Source: fmt.Sprintf("%s (%s)", source, path)}
+
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return layer, nil
+	} else if err != nil {
+		return Layer{}, errors.SafeWrap(err, "failed to read "+source)
+	}
+
+	if err := yaml.UnmarshalStrict(contents, &layer.Values); err != nil {
+		return Layer{}, errors.SafeWrap(fmt.Errorf("%s: %s", path, err), "failed to parse "+source)
+	}
+
+	if layer.Values.DepsFile != "" && !filepath.IsAbs(layer.Values.DepsFile) {
+		layer.Values.DepsFile = filepath.Join(filepath.Dir(path), layer.Values.DepsFile)
+	}
+	for i, caCert := range layer.Values.CACerts {
+		if !filepath.IsAbs(caCert) {
+			layer.Values.CACerts[i] = filepath.Join(filepath.Dir(path), caCert)
+		}
+	}
+	for host, settings := range layer.Values.RegistrySettings {
+		if settings.CACert != "" && !filepath.IsAbs(settings.CACert) {
+			settings.CACert = filepath.Join(filepath.Dir(path), settings.CACert)
+			layer.Values.RegistrySettings[host] = settings
+		}
+	}
+	return layer, nil
+}
@@ -0,0 +1,173 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/cfdev/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("start config", func() {
+	var (
+		homeDir    string
+		projectDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = ioutil.TempDir("", "cfdev-home")
+		Expect(err).NotTo(HaveOccurred())
+		projectDir, err = ioutil.TempDir("", "cfdev-project")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(homeDir)
+		os.RemoveAll(projectDir)
+		os.Unsetenv("CFDEV_CPUS")
+		os.Unsetenv("CFDEV_MEMORY")
+		os.Unsetenv("CFDEV_REGISTRIES")
+		os.Unsetenv("CFDEV_HOST_CA_CERTS")
+	})
+
+	Describe("ResolveStartConfig", func() {
+		It("uses the built-in defaults when nothing is set", func() {
+			c := config.ResolveStartConfig()
+			Expect(c.Cpus).To(Equal(config.DefaultCpus))
+			Expect(c.Memory).To(Equal(config.DefaultMemory))
+			Expect(c.SystemDomain).To(Equal("dev.cfdev.sh"))
+			Expect(c.Registries).To(BeEmpty())
+			Expect(c.Sources).To(HaveKeyWithValue("cpus", "default"))
+			Expect(c.Sources).To(HaveKeyWithValue("services", "default"))
+		})
+
+		It("takes every value from the first layer that sets it", func() {
+			c := config.ResolveStartConfig(
+				config.Layer{Source: "flag", Values: config.StartFile{Cpus: 2}},
+				config.Layer{Source: "env", Values: config.StartFile{Cpus: 3, Memory: 5000}},
+				config.Layer{Source: "iso metadata", Values: config.StartFile{Memory: 6000, Services: []string{"mysql"}}},
+			)
+			Expect(c.Cpus).To(Equal(2))
+			Expect(c.Memory).To(Equal(5000))
+			Expect(c.Services).To(ConsistOf("mysql"))
+			Expect(c.Sources).To(HaveKeyWithValue("cpus", "flag"))
+			Expect(c.Sources).To(HaveKeyWithValue("memory", "env"))
+			Expect(c.Sources).To(HaveKeyWithValue("services", "iso metadata"))
+		})
+	})
+
+	Describe("StartLayers", func() {
+		It("orders env before the project file before the user file", func() {
+			Expect(ioutil.WriteFile(filepath.Join(homeDir, "config.yml"), []byte("cpus: 6\nmemory: 8192\ndeps_file: deps.iso\n"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(projectDir, "cfdev.yml"), []byte("cpus: 2\nmemory: 4096\n"), 0644)).To(Succeed())
+			os.Setenv("CFDEV_MEMORY", "3000")
+			os.Setenv("CFDEV_REGISTRIES", "host:5000,host2:5000")
+
+			layers, err := config.StartLayers(homeDir, projectDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			c := config.ResolveStartConfig(layers...)
+			Expect(c.Memory).To(Equal(3000))
+			Expect(c.Registries).To(Equal([]string{"host:5000", "host2:5000"}))
+			Expect(c.Cpus).To(Equal(2))
+			Expect(c.DepsFile).To(Equal(filepath.Join(homeDir, "deps.iso")))
+			Expect(c.Sources).To(HaveKeyWithValue("memory", "env"))
+			Expect(c.Sources).To(HaveKeyWithValue("cpus", "project file ("+filepath.Join(projectDir, "cfdev.yml")+")"))
+			Expect(c.Sources).To(HaveKeyWithValue("deps_file", "user file ("+filepath.Join(homeDir, "config.yml")+")"))
+		})
+
+		It("reads the registry settings and resolves their CA certs next to the file", func() {
+			Expect(ioutil.WriteFile(filepath.Join(homeDir, "config.yml"), []byte(
+				"registry_mirror: https://mirror.example.com\nregistry_settings:\n  registry.example.com:5000:\n    ca_cert: ca.pem\n",
+			), 0644)).To(Succeed())
+
+			layers, err := config.StartLayers(homeDir, projectDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			c := config.ResolveStartConfig(layers...)
+			Expect(c.RegistryMirror).To(Equal("https://mirror.example.com"))
+			Expect(c.RegistrySettings).To(Equal(map[string]config.RegistrySettings{
+				"registry.example.com:5000": {CACert: filepath.Join(homeDir, "ca.pem")},
+			}))
+			Expect(c.Value("registry_settings")).To(Equal("registry.example.com:5000"))
+		})
+
+		It("does not take registry credentials", func() {
+			Expect(ioutil.WriteFile(filepath.Join(homeDir, "config.yml"), []byte(
+				"registry_settings:\n  registry.example.com:5000:\n    username: user\n    password: secret\n",
+			), 0644)).To(Succeed())
+
+			_, err := config.StartLayers(homeDir, projectDir)
+			Expect(err).To(MatchError(ContainSubstring("field username not found")))
+		})
+
+		It("rejects unknown keys in a config file", func() {
+			Expect(ioutil.WriteFile(filepath.Join(projectDir, "cfdev.yml"), []byte("cpu: 2\n"), 0644)).To(Succeed())
+
+			_, err := config.StartLayers(homeDir, projectDir)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse project file")))
+		})
+
+		It("lets a false host_ca_certs override a true one", func() {
+			Expect(ioutil.WriteFile(filepath.Join(homeDir, "config.yml"), []byte("host_ca_certs: true\n"), 0644)).To(Succeed())
+
+			layers, err := config.StartLayers(homeDir, projectDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.ResolveStartConfig(layers...).TrustHostCACerts()).To(BeTrue())
+
+			os.Setenv("CFDEV_HOST_CA_CERTS", "false")
+			layers, err = config.StartLayers(homeDir, projectDir)
+			Expect(err).NotTo(HaveOccurred())
+			c := config.ResolveStartConfig(layers...)
+			Expect(c.TrustHostCACerts()).To(BeFalse())
+			Expect(c.Sources).To(HaveKeyWithValue("host_ca_certs", "env"))
+
+			enabled := true
+			c = config.ResolveStartConfig(append([]config.Layer{{Source: config.SourceFlag, Values: config.StartFile{HostCACerts: &enabled}}}, layers...)...)
+			Expect(c.TrustHostCACerts()).To(BeTrue())
+			Expect(c.Value("host_ca_certs")).To(Equal("true"))
+		})
+
+		It("rejects env values that are not numbers", func() {
+			os.Setenv("CFDEV_CPUS", "lots")
+
+			_, err := config.StartLayers(homeDir, projectDir)
+			Expect(err).To(MatchError("CFDEV_CPUS must be a number: lots"))
+		})
+	})
+
+	Describe("NewConfig network settings", func() {
+		BeforeEach(func() {
+			os.Setenv("CFDEV_HOME", homeDir)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("CFDEV_HOME")
+			os.Unsetenv("CFDEV_ROUTER_IP")
+		})
+
+		It("takes the addresses from the env and the user file", func() {
+			Expect(ioutil.WriteFile(filepath.Join(homeDir, "config.yml"), []byte(
+				"bosh_director_ip: 172.20.0.2\nrouter_ip: 172.21.0.1\ncontainer_network: 172.22.0.0/16\n",
+			), 0644)).To(Succeed())
+			os.Setenv("CFDEV_ROUTER_IP", "172.21.0.34")
+
+			conf, err := config.NewConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf.BoshDirectorIP).To(Equal("172.20.0.2"))
+			Expect(conf.CFRouterIP).To(Equal("172.21.0.34"))
+			Expect(conf.HostIP).To(Equal(config.DefaultHostIP))
+			Expect(conf.ContainerNetwork).To(Equal("172.22.0.0/16"))
+		})
+
+		It("rejects addresses that are not valid", func() {
+			os.Setenv("CFDEV_ROUTER_IP", "not-an-ip")
+
+			_, err := config.NewConfig()
+			Expect(err).To(MatchError("router_ip must be an IPv4 address: not-an-ip (env)"))
+		})
+	})
+})
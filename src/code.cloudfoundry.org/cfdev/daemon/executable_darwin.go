@@ -0,0 +1,15 @@
+package daemon
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func executablePath(pid int) (string, error) {
+	output, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,15 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func executablePath(pid int) (string, error) {
+	path, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, " (deleted)"), nil
+}
@@ -338,13 +338,3 @@ func loadedDaemons() string {
 	Eventually(session).Should(gexec.Exit(0))
 	return string(session.Out.Contents())
 }
-
-var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
-
-func randomDaemonName() string {
-	b := make([]rune, 10)
-	for i := range b {
-		b[i] = letterRunes[rand.Intn(len(letterRunes))]
-	}
-	return "some-daemon" + string(b)
-}
@@ -4,6 +4,8 @@ import (
 	"math/rand"
 	"time"
 
+	_ "code.cloudfoundry.org/cfdev/daemon/supervise"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -18,3 +20,13 @@ func TestDaemon(t *testing.T) {
 var _ = BeforeSuite(func() {
 	rand.Seed(time.Now().UnixNano())
 })
+
+var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+func randomDaemonName() string {
+	b := make([]rune, 10)
+	for i := range b {
+		b[i] = letterRunes[rand.Intn(len(letterRunes))]
+	}
+	return "some-daemon" + string(b)
+}
@@ -0,0 +1,241 @@
+// +build !windows
+
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	SupervisorLabelEnv = "CFDEV_SUPERVISE_LABEL"
+	SupervisorDirEnv   = "CFDEV_SUPERVISE_DIR"
+
+	startTimeout = 10 * time.Second
+	stopTimeout  = 10 * time.Second
+)
+
+type Process struct {
+	StateDir       string
+	SupervisorPath string
+}
+
+func NewProcess(cfDevHome string) *Process {
+	return &Process{
+		StateDir: filepath.Join(cfDevHome, "daemons"),
+	}
+}
+
+func (p *Process) AddDaemon(spec DaemonSpec) error {
+	p.RemoveDaemon(spec.Label)
+	if err := os.MkdirAll(p.dir(spec.Label), 0755); err != nil {
+		return err
+	}
+	if spec.StdoutPath == "" {
+		spec.StdoutPath = filepath.Join(p.dir(spec.Label), "stdout.log")
+	}
+	if spec.StderrPath == "" {
+		spec.StderrPath = filepath.Join(p.dir(spec.Label), "stderr.log")
+	}
+	contents, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(p.specPath(spec.Label), contents, 0644); err != nil {
+		return err
+	}
+	if spec.RunAtLoad {
+		return p.Start(spec.Label)
+	}
+	return nil
+}
+
+func (p *Process) RemoveDaemon(label string) error {
+	if err := p.Stop(label); err != nil {
+		return err
+	}
+	return os.RemoveAll(p.dir(label))
+}
+
+func (p *Process) Start(label string) error {
+	if running, _ := p.IsRunning(label); running {
+		return nil
+	}
+
+	if _, err := p.spec(label); err != nil {
+		return err
+	}
+
+	supervisor, err := p.supervisorPath()
+	if err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(p.supervisorLogPath(label), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+
+	cmd := exec.Command(supervisor)
+	cmd.Env = append(os.Environ(), SupervisorLabelEnv+"="+label, SupervisorDirEnv+"="+p.StateDir)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	timeout := time.After(startTimeout)
+	for {
+		select {
+		case err := <-exited:
+			if running, _ := p.IsRunning(label); running {
+				return nil
+			}
+			return fmt.Errorf("supervisor for %s exited: %v, see %s", label, err, p.supervisorLogPath(label))
+		case <-timeout:
+			return fmt.Errorf("timed out waiting for %s to start", label)
+		case <-time.After(100 * time.Millisecond):
+			if running, _ := p.IsRunning(label); running {
+				return nil
+			}
+		}
+	}
+}
+
+func (p *Process) Stop(label string) error {
+	if pid, err := readPid(p.supervisorPidPath(label)); err == nil && p.isSupervisor(pid) {
+		if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+			return err
+		}
+		for start := time.Now(); time.Since(start) < stopTimeout+time.Second && p.isSupervisor(pid); {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if p.isSupervisor(pid) {
+			syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+
+	if running, _ := p.IsRunning(label); running {
+		pid, err := readPid(p.pidPath(label))
+		if err != nil {
+			return err
+		}
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range []string{p.pidPath(label), p.supervisorPidPath(label)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Process) IsRunning(label string) (bool, error) {
+	pid, err := readPid(p.pidPath(label))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	spec, err := p.spec(label)
+	if err != nil {
+		return false, err
+	}
+	return sameExecutable(pid, spec.Program), nil
+}
+
+func (p *Process) isSupervisor(pid int) bool {
+	supervisor, err := p.supervisorPath()
+	if err != nil {
+		return false
+	}
+	return sameExecutable(pid, supervisor)
+}
+
+func (p *Process) supervisorPath() (string, error) {
+	if p.SupervisorPath != "" {
+		return p.SupervisorPath, nil
+	}
+	return os.Executable()
+}
+
+func (p *Process) spec(label string) (DaemonSpec, error) {
+	var spec DaemonSpec
+	contents, err := ioutil.ReadFile(p.specPath(label))
+	if err != nil {
+		return spec, err
+	}
+	err = json.Unmarshal(contents, &spec)
+	return spec, err
+}
+
+func (p *Process) dir(label string) string {
+	return filepath.Join(p.StateDir, label)
+}
+
+func (p *Process) specPath(label string) string {
+	return filepath.Join(p.dir(label), "spec.json")
+}
+
+func (p *Process) pidPath(label string) string {
+	return filepath.Join(p.dir(label), label+".pid")
+}
+
+func (p *Process) supervisorPidPath(label string) string {
+	return filepath.Join(p.dir(label), "supervisor.pid")
+}
+
+func (p *Process) supervisorLogPath(label string) string {
+	return filepath.Join(p.dir(label), "supervisor.log")
+}
+
+func readPid(path string) (int, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func writePid(path string, pid int) error {
+	return ioutil.WriteFile(path, []byte(strconv.Itoa(pid)), 0644)
+}
+
+func sameExecutable(pid int, program string) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	path, err := executablePath(pid)
+	if err != nil || path == "" {
+		return false
+	}
+	if path == program {
+		return true
+	}
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	resolvedProgram, err := filepath.EvalSymlinks(program)
+	if err != nil {
+		return false
+	}
+	return resolvedPath == resolvedProgram
+}
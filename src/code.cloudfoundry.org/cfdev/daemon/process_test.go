@@ -0,0 +1,156 @@
+// +build !windows
+
+package daemon_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"code.cloudfoundry.org/cfdev/daemon"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Process", func() {
+	var (
+		stateDir string
+		label    string
+		process  *daemon.Process
+		spec     daemon.DaemonSpec
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "process")
+		Expect(err).NotTo(HaveOccurred())
+		label = randomDaemonName()
+		process = &daemon.Process{StateDir: stateDir}
+		spec = daemon.DaemonSpec{
+			Label:            label,
+			Program:          "/bin/sleep",
+			ProgramArguments: []string{"/bin/sleep", "36000"},
+			StdoutPath:       filepath.Join(stateDir, "stdout.log"),
+			StderrPath:       filepath.Join(stateDir, "stderr.log"),
+		}
+	})
+
+	AfterEach(func() {
+		process.RemoveDaemon(label)
+		os.RemoveAll(stateDir)
+	})
+
+	Describe("AddDaemon", func() {
+		It("records the spec without starting the program", func() {
+			Expect(process.AddDaemon(spec)).To(Succeed())
+			Expect(filepath.Join(stateDir, label, "spec.json")).To(BeAnExistingFile())
+			Expect(process.IsRunning(label)).To(BeFalse())
+		})
+	})
+
+	Describe("Start", func() {
+		BeforeEach(func() {
+			Expect(process.AddDaemon(spec)).To(Succeed())
+		})
+
+		It("runs the program under a detached supervisor and records its pid", func() {
+			Expect(process.Start(label)).To(Succeed())
+			Expect(filepath.Join(stateDir, label, label+".pid")).To(BeAnExistingFile())
+			Expect(filepath.Join(stateDir, label, "supervisor.pid")).To(BeAnExistingFile())
+			Expect(process.IsRunning(label)).To(BeTrue())
+		})
+
+		Context("when the pidfile belongs to a different program", func() {
+			It("is not considered running", func() {
+				Expect(ioutil.WriteFile(filepath.Join(stateDir, label, label+".pid"), []byte(strconv.Itoa(os.Getpid())), 0644)).To(Succeed())
+				Expect(process.IsRunning(label)).To(BeFalse())
+			})
+		})
+
+		Context("when the daemon was never added", func() {
+			It("returns an error", func() {
+				Expect(process.Start("some-unknown-label")).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("Stop", func() {
+		BeforeEach(func() {
+			Expect(process.AddDaemon(spec)).To(Succeed())
+			Expect(process.Start(label)).To(Succeed())
+		})
+
+		It("terminates the program", func() {
+			Expect(process.Stop(label)).To(Succeed())
+			Expect(process.IsRunning(label)).To(BeFalse())
+			Expect(filepath.Join(stateDir, label, label+".pid")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("restart policy", func() {
+		var pid = func() int {
+			data, err := ioutil.ReadFile(filepath.Join(stateDir, label, label+".pid"))
+			if err != nil {
+				return 0
+			}
+			pid, _ := strconv.Atoi(string(data))
+			return pid
+		}
+
+		Context("when the policy is always", func() {
+			BeforeEach(func() {
+				spec.Restart = daemon.RestartAlways
+				Expect(process.AddDaemon(spec)).To(Succeed())
+				Expect(process.Start(label)).To(Succeed())
+			})
+
+			It("restarts the program after it is killed", func() {
+				firstPid := pid()
+				Expect(syscall.Kill(firstPid, syscall.SIGKILL)).To(Succeed())
+
+				Eventually(pid, "5s").ShouldNot(SatisfyAny(Equal(0), Equal(firstPid)))
+				Eventually(func() (bool, error) { return process.IsRunning(label) }, "5s").Should(BeTrue())
+			})
+		})
+
+		Context("when the policy is on-failure with a restart limit", func() {
+			var marker string
+
+			BeforeEach(func() {
+				marker = filepath.Join(stateDir, "runs")
+				spec.Program = "/bin/sh"
+				spec.ProgramArguments = []string{"/bin/sh", "-c", "echo run >> " + marker + "; sleep 1; exit 1"}
+				spec.Restart = daemon.RestartOnFailure
+				spec.MaxRestarts = 2
+				Expect(process.AddDaemon(spec)).To(Succeed())
+				Expect(process.Start(label)).To(Succeed())
+			})
+
+			It("restarts the program until the limit is reached", func() {
+				runs := func() int {
+					data, _ := ioutil.ReadFile(marker)
+					return strings.Count(string(data), "run")
+				}
+				Eventually(runs, "10s").Should(Equal(3))
+				Eventually(func() (bool, error) { return process.IsRunning(label) }, "5s").Should(BeFalse())
+				Consistently(runs, "2s").Should(Equal(3))
+			})
+		})
+	})
+
+	Describe("RemoveDaemon", func() {
+		BeforeEach(func() {
+			Expect(process.AddDaemon(spec)).To(Succeed())
+			Expect(process.Start(label)).To(Succeed())
+		})
+
+		It("stops the program and removes its state", func() {
+			Expect(process.RemoveDaemon(label)).To(Succeed())
+			Expect(process.IsRunning(label)).To(BeFalse())
+			Expect(filepath.Join(stateDir, label)).NotTo(BeADirectory())
+		})
+	})
+})
@@ -1,5 +1,11 @@
 package daemon
 
+const (
+	RestartNever     = ""
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
 type DaemonSpec struct {
 	Label            string
 	Program          string
@@ -9,4 +15,6 @@ type DaemonSpec struct {
 	Sockets          map[string]string
 	StdoutPath       string
 	StderrPath       string
+	Restart          string
+	MaxRestarts      int
 }
@@ -0,0 +1,126 @@
+// +build !windows
+
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const restartDelay = time.Second
+
+func Supervise(stateDir, label string) error {
+	p := &Process{StateDir: stateDir}
+	spec, err := p.spec(label)
+	if err != nil {
+		return err
+	}
+
+	if err := writePid(p.supervisorPidPath(label), os.Getpid()); err != nil {
+		return err
+	}
+	defer os.Remove(p.supervisorPidPath(label))
+
+	signal.Ignore(syscall.SIGHUP)
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+
+	restarts := 0
+	for {
+		cmd, err := p.spawn(spec)
+		if err != nil {
+			return err
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+
+		select {
+		case sig := <-sigs:
+			cmd.Process.Signal(sig)
+			select {
+			case <-done:
+			case <-time.After(stopTimeout):
+				cmd.Process.Kill()
+				<-done
+			}
+			return os.Remove(p.pidPath(label))
+		case err := <-done:
+			os.Remove(p.pidPath(label))
+			if !shouldRestart(spec, err, restarts) {
+				return nil
+			}
+			restarts++
+		}
+
+		select {
+		case <-sigs:
+			return nil
+		case <-time.After(restartDelay):
+		}
+	}
+}
+
+func shouldRestart(spec DaemonSpec, exitErr error, restarts int) bool {
+	if spec.MaxRestarts > 0 && restarts >= spec.MaxRestarts {
+		return false
+	}
+	switch spec.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return exitErr != nil
+	default:
+		return false
+	}
+}
+
+func (p *Process) spawn(spec DaemonSpec) (*exec.Cmd, error) {
+	stdout, err := openLog(spec.StdoutPath)
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.Close()
+	stderr, err := openLog(spec.StderrPath)
+	if err != nil {
+		return nil, err
+	}
+	defer stderr.Close()
+
+	var args []string
+	if len(spec.ProgramArguments) > 1 {
+		args = spec.ProgramArguments[1:]
+	}
+	cmd := exec.Command(spec.Program, args...)
+	cmd.Env = childEnv()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	if err := writePid(p.pidPath(spec.Label), cmd.Process.Pid); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	return cmd, nil
+}
+
+func childEnv() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, SupervisorLabelEnv+"=") && !strings.HasPrefix(e, SupervisorDirEnv+"=") {
+			env = append(env, e)
+		}
+	}
+	return env
+}
+
+func openLog(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+}
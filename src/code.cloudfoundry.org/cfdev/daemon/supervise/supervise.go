@@ -0,0 +1,23 @@
+// +build !windows
+
+package supervise
+
+import (
+	"fmt"
+	"os"
+
+	"code.cloudfoundry.org/cfdev/daemon"
+)
+
+func init() {
+	label := os.Getenv(daemon.SupervisorLabelEnv)
+	if label == "" {
+		return
+	}
+
+	if err := daemon.Supervise(os.Getenv(daemon.SupervisorDirEnv), label); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,240 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+type Systemd struct {
+	UnitDir   string
+	Systemctl string
+	System    bool
+}
+
+func NewSystemd(unitDir string) *Systemd {
+	if unitDir == "" {
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(os.Getenv("HOME"), ".config")
+		}
+		unitDir = filepath.Join(configHome, "systemd", "user")
+	}
+
+	return &Systemd{
+		UnitDir:   unitDir,
+		Systemctl: "systemctl",
+	}
+}
+
+func NewSystemSystemd() *Systemd {
+	return &Systemd{
+		UnitDir:   "/etc/systemd/system",
+		Systemctl: "systemctl",
+		System:    true,
+	}
+}
+
+func SystemdAvailable() bool {
+	return SystemSystemdAvailable() && os.Getenv("XDG_RUNTIME_DIR") != ""
+}
+
+func SystemSystemdAvailable() bool {
+	if _, err := os.Stat("/run/systemd/system"); err != nil {
+		return false
+	}
+	_, err := exec.LookPath("systemctl")
+	return err == nil
+}
+
+type socketUnit struct {
+	Label string
+	Name  string
+	Path  string
+}
+
+func (s *Systemd) AddDaemon(spec DaemonSpec) error {
+	s.RemoveDaemon(spec.Label)
+
+	if err := os.MkdirAll(s.UnitDir, 0755); err != nil {
+		return err
+	}
+	if err := s.writeUnit(serviceTemplate, s.serviceUnit(spec.Label), spec); err != nil {
+		return err
+	}
+	sockets := socketUnits(spec)
+	for _, socket := range sockets {
+		if err := s.writeUnit(socketTemplate, s.socketUnit(socket.Label, socket.Name), socket); err != nil {
+			return err
+		}
+	}
+
+	if err := s.systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	for _, socket := range sockets {
+		if err := s.systemctl("start", s.socketUnit(socket.Label, socket.Name)); err != nil {
+			return err
+		}
+	}
+	if spec.RunAtLoad {
+		return s.systemctl("start", s.serviceUnit(spec.Label))
+	}
+	return nil
+}
+
+func (s *Systemd) RemoveDaemon(label string) error {
+	units := []string{s.serviceUnit(label)}
+	sockets, err := filepath.Glob(filepath.Join(s.UnitDir, label+"-*.socket"))
+	if err != nil {
+		return err
+	}
+	for _, socket := range sockets {
+		units = append(units, filepath.Base(socket))
+	}
+
+	if _, err := os.Stat(filepath.Join(s.UnitDir, s.serviceUnit(label))); os.IsNotExist(err) && len(sockets) == 0 {
+		return nil
+	}
+
+	s.systemctl(append([]string{"stop"}, units...)...)
+	for _, unit := range units {
+		if err := os.Remove(filepath.Join(s.UnitDir, unit)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return s.systemctl("daemon-reload")
+}
+
+func (s *Systemd) Start(label string) error {
+	return s.systemctl("start", s.serviceUnit(label))
+}
+
+func (s *Systemd) Stop(label string) error {
+	if running, _ := s.IsRunning(label); !running {
+		return nil
+	}
+	return s.systemctl("stop", s.serviceUnit(label))
+}
+
+func (s *Systemd) IsRunning(label string) (bool, error) {
+	output, err := exec.Command(s.Systemctl, s.args("is-active", s.serviceUnit(label))...).Output()
+	state := strings.TrimSpace(string(output))
+	if state == "" && err != nil {
+		return false, err
+	}
+	return state == "active" || state == "reloading", nil
+}
+
+func (s *Systemd) systemctl(args ...string) error {
+	cmd := exec.Command(s.Systemctl, s.args(args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("systemctl %s: %s: %s", strings.Join(args, " "), err, string(output))
+	}
+	return nil
+}
+
+func (s *Systemd) args(args ...string) []string {
+	if s.System {
+		return args
+	}
+	return append([]string{"--user"}, args...)
+}
+
+func (s *Systemd) writeUnit(unitTemplate, name string, data interface{}) error {
+	tmplt := template.Must(template.New("unit").Funcs(template.FuncMap{
+		"execStart": execStart,
+		"unitName":  s.serviceUnit,
+		"wantedBy":  s.wantedBy,
+	}).Parse(unitTemplate))
+	unit, err := os.Create(filepath.Join(s.UnitDir, name))
+	if err != nil {
+		return err
+	}
+	defer unit.Close()
+	return tmplt.Execute(unit, data)
+}
+
+func (s *Systemd) serviceUnit(label string) string {
+	return label + ".service"
+}
+
+func (s *Systemd) wantedBy() string {
+	if s.System {
+		return "multi-user.target"
+	}
+	return "default.target"
+}
+
+func (s *Systemd) socketUnit(label, name string) string {
+	return label + "-" + name + ".socket"
+}
+
+func socketUnits(spec DaemonSpec) []socketUnit {
+	var sockets []socketUnit
+	for name, path := range spec.Sockets {
+		sockets = append(sockets, socketUnit{Label: spec.Label, Name: name, Path: path})
+	}
+	sort.Slice(sockets, func(i, j int) bool {
+		return sockets[i].Name < sockets[j].Name
+	})
+	return sockets
+}
+
+func execStart(spec DaemonSpec) string {
+	args := []string{quoteUnitArg(spec.Program)}
+	if len(spec.ProgramArguments) > 1 {
+		for _, arg := range spec.ProgramArguments[1:] {
+			args = append(args, quoteUnitArg(arg))
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+func quoteUnitArg(arg string) string {
+	arg = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(arg)
+	if arg == "" || strings.ContainsAny(arg, " \t'\"\\") {
+		return `"` + arg + `"`
+	}
+	return arg
+}
+
+var serviceTemplate = `[Unit]
+Description={{.Label}}
+{{- range $name, $path := .Sockets}}
+Requires={{$.Label}}-{{$name}}.socket
+After={{$.Label}}-{{$name}}.socket
+{{- end}}
+
+[Service]
+ExecStart={{execStart .}}
+{{- if .Restart}}
+Restart={{.Restart}}
+{{- end}}
+{{- if .StdoutPath}}
+StandardOutput=file:{{.StdoutPath}}
+{{- end}}
+{{- if .StderrPath}}
+StandardError=file:{{.StderrPath}}
+{{- end}}
+
+[Install]
+WantedBy={{wantedBy}}
+`
+
+var socketTemplate = `[Unit]
+Description={{.Label}} {{.Name}} socket
+
+[Socket]
+ListenStream={{.Path}}
+SocketMode=0666
+FileDescriptorName={{.Name}}
+Service={{unitName .Label}}
+
+[Install]
+WantedBy=sockets.target
+`
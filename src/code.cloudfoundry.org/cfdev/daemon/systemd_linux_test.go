@@ -0,0 +1,230 @@
+package daemon_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/daemon"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Systemd", func() {
+	var (
+		tmpDir   string
+		unitDir  string
+		callsLog string
+		label    string
+		systemd  *daemon.Systemd
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "systemd")
+		Expect(err).NotTo(HaveOccurred())
+		unitDir = filepath.Join(tmpDir, "units")
+		callsLog = filepath.Join(tmpDir, "calls.log")
+		label = randomDaemonName()
+
+		fakeSystemctl := filepath.Join(tmpDir, "systemctl")
+		Expect(ioutil.WriteFile(fakeSystemctl, []byte(fmt.Sprintf(`#!/bin/sh
+echo "$@" >> %s
+if [ "$2" = "is-active" ]; then
+  cat %s/state 2>/dev/null || echo inactive
+fi
+`, callsLog, tmpDir)), 0755)).To(Succeed())
+
+		systemd = &daemon.Systemd{
+			UnitDir:   unitDir,
+			Systemctl: fakeSystemctl,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	calls := func() []string {
+		contents, _ := ioutil.ReadFile(callsLog)
+		return strings.Split(strings.TrimSpace(string(contents)), "\n")
+	}
+
+	Describe("AddDaemon", func() {
+		It("writes a user service unit and reloads systemd", func() {
+			spec := daemon.DaemonSpec{
+				Label:            label,
+				Program:          "/some/program",
+				ProgramArguments: []string{"/some/program", "--some-flag", "some arg with spaces"},
+				StdoutPath:       "/some/stdout.log",
+				StderrPath:       "/some/stderr.log",
+			}
+
+			Expect(systemd.AddDaemon(spec)).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(unitDir, label+".service"))).To(BeEquivalentTo(fmt.Sprintf(`[Unit]
+Description=%s
+
+[Service]
+ExecStart=/some/program --some-flag "some arg with spaces"
+StandardOutput=file:/some/stdout.log
+StandardError=file:/some/stderr.log
+
+[Install]
+WantedBy=default.target
+`, label)))
+			Expect(calls()).To(Equal([]string{"--user daemon-reload"}))
+		})
+
+		It("starts the service when RunAtLoad is set", func() {
+			spec := daemon.DaemonSpec{
+				Label:            label,
+				Program:          "/some/program",
+				ProgramArguments: []string{"/some/program"},
+				RunAtLoad:        true,
+			}
+
+			Expect(systemd.AddDaemon(spec)).To(Succeed())
+
+			Expect(calls()).To(Equal([]string{
+				"--user daemon-reload",
+				"--user start " + label + ".service",
+			}))
+		})
+
+		It("writes and starts a socket unit for each socket", func() {
+			spec := daemon.DaemonSpec{
+				Label:            label,
+				Program:          "/some/program",
+				ProgramArguments: []string{"/some/program"},
+				Sockets: map[string]string{
+					"CoolSocket": "/var/tmp/my.cool.socket",
+				},
+			}
+
+			Expect(systemd.AddDaemon(spec)).To(Succeed())
+
+			Expect(ioutil.ReadFile(filepath.Join(unitDir, label+".service"))).To(ContainSubstring(
+				"Requires=" + label + "-CoolSocket.socket",
+			))
+			Expect(ioutil.ReadFile(filepath.Join(unitDir, label+"-CoolSocket.socket"))).To(BeEquivalentTo(fmt.Sprintf(`[Unit]
+Description=%s CoolSocket socket
+
+[Socket]
+ListenStream=/var/tmp/my.cool.socket
+SocketMode=0666
+FileDescriptorName=CoolSocket
+Service=%s.service
+
+[Install]
+WantedBy=sockets.target
+`, label, label)))
+			Expect(calls()).To(Equal([]string{
+				"--user daemon-reload",
+				"--user start " + label + "-CoolSocket.socket",
+			}))
+		})
+
+		Context("when managing a system daemon", func() {
+			BeforeEach(func() {
+				systemd.System = true
+			})
+
+			It("writes a system service unit and calls systemctl without --user", func() {
+				spec := daemon.DaemonSpec{
+					Label:            label,
+					Program:          "/some/program",
+					ProgramArguments: []string{"/some/program"},
+					Sockets: map[string]string{
+						"CoolSocket": "/var/run/my.cool.socket",
+					},
+				}
+
+				Expect(systemd.AddDaemon(spec)).To(Succeed())
+
+				Expect(ioutil.ReadFile(filepath.Join(unitDir, label+".service"))).To(ContainSubstring(
+					"WantedBy=multi-user.target",
+				))
+				Expect(calls()).To(Equal([]string{
+					"daemon-reload",
+					"start " + label + "-CoolSocket.socket",
+				}))
+			})
+		})
+	})
+
+	Describe("RemoveDaemon", func() {
+		Context("when the units exist", func() {
+			BeforeEach(func() {
+				Expect(os.MkdirAll(unitDir, 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(unitDir, label+".service"), []byte{}, 0644)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(unitDir, label+"-CoolSocket.socket"), []byte{}, 0644)).To(Succeed())
+			})
+
+			It("stops the units and removes the files", func() {
+				Expect(systemd.RemoveDaemon(label)).To(Succeed())
+
+				Expect(filepath.Join(unitDir, label+".service")).NotTo(BeAnExistingFile())
+				Expect(filepath.Join(unitDir, label+"-CoolSocket.socket")).NotTo(BeAnExistingFile())
+				Expect(calls()).To(Equal([]string{
+					"--user stop " + label + ".service " + label + "-CoolSocket.socket",
+					"--user daemon-reload",
+				}))
+			})
+		})
+
+		Context("when the units do not exist", func() {
+			It("succeeds without calling systemctl", func() {
+				Expect(systemd.RemoveDaemon(label)).To(Succeed())
+				Expect(callsLog).NotTo(BeAnExistingFile())
+			})
+		})
+	})
+
+	Describe("Start", func() {
+		It("starts the service", func() {
+			Expect(systemd.Start(label)).To(Succeed())
+			Expect(calls()).To(Equal([]string{"--user start " + label + ".service"}))
+		})
+	})
+
+	Describe("IsRunning", func() {
+		Context("when the service is active", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(filepath.Join(tmpDir, "state"), []byte("active\n"), 0644)).To(Succeed())
+			})
+
+			It("returns true", func() {
+				Expect(systemd.IsRunning(label)).To(BeTrue())
+			})
+		})
+
+		Context("when the service is inactive", func() {
+			It("returns false", func() {
+				Expect(systemd.IsRunning(label)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Stop", func() {
+		Context("when the service is active", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(filepath.Join(tmpDir, "state"), []byte("active\n"), 0644)).To(Succeed())
+			})
+
+			It("stops the service", func() {
+				Expect(systemd.Stop(label)).To(Succeed())
+				Expect(calls()).To(ContainElement("--user stop " + label + ".service"))
+			})
+		})
+
+		Context("when the service is not active", func() {
+			It("does nothing", func() {
+				Expect(systemd.Stop(label)).To(Succeed())
+				Expect(calls()).NotTo(ContainElement(ContainSubstring("stop")))
+			})
+		})
+	})
+})
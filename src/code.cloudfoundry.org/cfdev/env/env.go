@@ -3,55 +3,11 @@ package env
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"code.cloudfoundry.org/cfdev/config"
 	"code.cloudfoundry.org/cfdev/errors"
 )
 
-type ProxyConfig struct {
-	Http    string `json:"http,omitempty"`
-	Https   string `json:"https,omitempty"`
-	NoProxy string `json:"exclude,omitempty"`
-}
-
-func BuildProxyConfig(boshDirectorIP string, cfRouterIP string, hostIP string) ProxyConfig {
-	httpProxy := os.Getenv("http_proxy")
-	if os.Getenv("HTTP_PROXY") != "" {
-		httpProxy = os.Getenv("HTTP_PROXY")
-	}
-
-	httpsProxy := os.Getenv("https_proxy")
-	if os.Getenv("HTTPS_PROXY") != "" {
-		httpsProxy = os.Getenv("HTTPS_PROXY")
-	}
-
-	noProxy := os.Getenv("no_proxy")
-	if os.Getenv("NO_PROXY") != "" {
-		noProxy = os.Getenv("NO_PROXY")
-	}
-
-	if boshDirectorIP != "" && !strings.Contains(noProxy, boshDirectorIP) {
-		noProxy = strings.Join([]string{noProxy, boshDirectorIP}, ",")
-	}
-
-	if cfRouterIP != "" && !strings.Contains(noProxy, cfRouterIP) {
-		noProxy = strings.Join([]string{noProxy, cfRouterIP}, ",")
-	}
-
-	if hostIP != "" && !strings.Contains(noProxy, hostIP) {
-		noProxy = strings.Join([]string{noProxy, hostIP}, ",")
-	}
-
-	proxyConfig := ProxyConfig{
-		Http:    httpProxy,
-		Https:   httpsProxy,
-		NoProxy: noProxy,
-	}
-
-	return proxyConfig
-}
-
 func SetupHomeDir(config config.Config) error {
 	if err := os.MkdirAll(config.CFDevHome, 0755); err != nil {
 		return errors.SafeWrap(fmt.Errorf("path %s: %s", config.CFDevHome, err), "failed to create cfdev home dir")
@@ -61,6 +17,10 @@ func SetupHomeDir(config config.Config) error {
 		return errors.SafeWrap(fmt.Errorf("path %s: %s", config.CacheDir, err), "failed to create cache dir")
 	}
 
+	if err := config.SaveEnv(); err != nil {
+		return err
+	}
+
 	for _, dir := range []string{config.StateDir, config.VpnKitStateDir} {
 		//remove any old state
 		if err := os.RemoveAll(dir); err != nil {
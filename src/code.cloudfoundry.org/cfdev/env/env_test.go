@@ -15,50 +15,6 @@ import (
 )
 
 var _ = Describe("env", func() {
-	Context("when proxy env vars are set", func() {
-		BeforeEach(func() {
-			os.Setenv("HTTP_PROXY", "some-http-proxy")
-			os.Setenv("HTTPS_PROXY", "some-https-proxy")
-			os.Setenv("NO_PROXY", "some-no-proxy")
-		})
-		AfterEach(func() {
-			os.Unsetenv("HTTP_PROXY")
-			os.Unsetenv("HTTPS_PROXY")
-			os.Unsetenv("NO_PROXY")
-		})
-		It("returns the http config", func() {
-			proxyConfig := env.BuildProxyConfig("bosh-ip", "router-ip", "host-ip")
-			Expect(proxyConfig.Http).To(Equal("some-http-proxy"))
-			Expect(proxyConfig.Https).To(Equal("some-https-proxy"))
-			Expect(proxyConfig.NoProxy).To(Equal("some-no-proxy,bosh-ip,router-ip,host-ip"))
-		})
-	})
-
-	Context("when multiple mixed case proxy envs prioritize uppercase", func() {
-		BeforeEach(func() {
-			os.Setenv("http_proxy", "lower-case-http-proxy")
-			os.Setenv("HTTP_PROXY", "upper-some-http-proxy")
-			os.Setenv("https_proxy", "lower-case-https-proxy")
-			os.Setenv("HTTPS_PROXY", "upper-some-https-proxy")
-			os.Setenv("no_proxy", "lower-some-no-proxy")
-			os.Setenv("NO_PROXY", "upper-some-no-proxy,bosh-ip,router-ip")
-		})
-		AfterEach(func() {
-			os.Unsetenv("http_proxy")
-			os.Unsetenv("HTTP_PROXY")
-			os.Unsetenv("https_proxy")
-			os.Unsetenv("HTTPS_PROXY")
-			os.Unsetenv("no_proxy")
-			os.Unsetenv("NO_PROXY")
-		})
-		It("returns the http config", func() {
-			proxyConfig := env.BuildProxyConfig("bosh-ip", "router-ip", "host-ip")
-			Expect(proxyConfig.Http).To(Equal("upper-some-http-proxy"))
-			Expect(proxyConfig.Https).To(Equal("upper-some-https-proxy"))
-			Expect(proxyConfig.NoProxy).To(Equal("upper-some-no-proxy,bosh-ip,router-ip,host-ip"))
-		})
-	})
-
 	Describe("SetupHomeDir", func() {
 		var dir, homeDir, cacheDir, stateDir, vpnkitStateDir string
 		var err error
@@ -0,0 +1,9 @@
+package host
+
+import "os/exec"
+
+// TrustedCerts returns the PEM encoded CA certs of the system keychain, where
+// device management installs corporate CAs
+func (h *Host) TrustedCerts() ([]byte, error) {
+	return exec.Command("security", "find-certificate", "-a", "-p", "/Library/Keychains/System.keychain").Output()
+}
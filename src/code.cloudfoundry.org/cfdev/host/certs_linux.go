@@ -0,0 +1,34 @@
+package host
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Distributions keep the CAs added by the administrator apart from the
+// bundle shipped with the OS
+var trustStoreDirs = []string{
+	"/usr/local/share/ca-certificates",
+	"/etc/pki/ca-trust/source/anchors",
+}
+
+// TrustedCerts returns the PEM encoded CA certs added to the host trust store
+func (h *Host) TrustedCerts() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, dir := range trustStoreDirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			contents, err := ioutil.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			buf.Write(contents)
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
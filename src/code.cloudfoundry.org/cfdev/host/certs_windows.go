@@ -0,0 +1,14 @@
+package host
+
+import "os/exec"
+
+const exportRootCertsCmd = `Get-ChildItem Cert:\LocalMachine\Root | ForEach-Object {
+  "-----BEGIN CERTIFICATE-----"
+  [Convert]::ToBase64String($_.RawData, 'InsertLineBreaks')
+  "-----END CERTIFICATE-----"
+}`
+
+// TrustedCerts returns the PEM encoded CA certs of the machine's root store
+func (h *Host) TrustedCerts() ([]byte, error) {
+	return exec.Command("powershell.exe", "-Command", exportRootCertsCmd).Output()
+}
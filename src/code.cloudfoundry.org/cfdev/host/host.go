@@ -1,3 +1,211 @@
 package host
 
-type Host struct {}
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/errors"
+)
+
+//go:generate mockgen -package mocks -destination mocks/ui.go code.cloudfoundry.org/cfdev/host UI
+type UI interface {
+	Say(message string, args ...interface{})
+}
+
+//go:generate mockgen -package mocks -destination mocks/probe.go code.cloudfoundry.org/cfdev/host Probe
+type Probe interface {
+	CPUs() int
+	Memory() (totalMB uint64, availableMB uint64, err error)
+	FreeDiskSpace(path string) (uint64, error)
+	LookPath(binary string) (string, error)
+	Routes() ([]Route, error)
+	Dial(address string) error
+}
+
+type Host struct {
+	UI    UI
+	Probe Probe
+}
+
+type Requirements struct {
+	VM          bool
+	CPUs        int
+	MemoryMB    int
+	CacheDir    string
+	CacheBytes  uint64
+	StateDir    string
+	VMDiskBytes uint64
+	Networks    []Network
+	Proxies     []Proxy
+}
+
+type Severity int
+
+const (
+	Warning Severity = iota
+	Fatal
+)
+
+type Failure struct {
+	Requirement string
+	Severity    Severity
+	Message     string
+	Remediation string
+}
+
+func (f Failure) Error() string {
+	if f.Remediation == "" {
+		return f.Message
+	}
+	return f.Message + ". " + f.Remediation
+}
+
+func (h *Host) CheckRequirements(req Requirements) error {
+	failures := h.Check(req)
+
+	var fatal []Failure
+	for _, failure := range failures {
+		if failure.Severity == Fatal {
+			fatal = append(fatal, failure)
+		} else if h.UI != nil {
+			h.UI.Say("WARNING: %s", failure.Error())
+		}
+	}
+
+	switch len(fatal) {
+	case 0:
+		return nil
+	case 1:
+		return errors.SafeWrap(fmt.Errorf("%s", fatal[0].Error()), fatal[0].Requirement)
+	default:
+		var names, messages []string
+		for _, failure := range fatal {
+			names = append(names, failure.Requirement)
+			messages = append(messages, "- "+failure.Error())
+		}
+		return errors.SafeWrap(
+			fmt.Errorf("\n%s", strings.Join(messages, "\n")),
+			"Host requirements not met ("+strings.Join(names, ", ")+")",
+		)
+	}
+}
+
+func (h *Host) Check(req Requirements) []Failure {
+	probe := h.probe()
+
+	var failures []Failure
+	add := func(failure *Failure) {
+		if failure != nil {
+			failures = append(failures, *failure)
+		}
+	}
+
+	add(checkCPUs(probe, req))
+	add(checkMemory(probe, req))
+	add(checkDisk(probe, req.CacheDir, req.CacheBytes, Fatal,
+		"Free up some disk space or set CFDEV_HOME to a location with more space"))
+	add(checkDisk(probe, req.StateDir, req.VMDiskBytes, Warning,
+		"The VM disk grows on demand and may fill the disk, free up some disk space or set CFDEV_HOME to a location with more space"))
+
+	if req.VM {
+		for _, binary := range requiredBinaries {
+			add(checkBinary(probe, binary))
+		}
+	}
+
+	failures = append(failures, checkNetworks(probe, req.Networks)...)
+	failures = append(failures, checkProxies(probe, req.Proxies)...)
+	return append(failures, h.platformChecks(req)...)
+}
+
+func (h *Host) probe() Probe {
+	if h.Probe == nil {
+		return &systemProbe{}
+	}
+	return h.Probe
+}
+
+func checkCPUs(probe Probe, req Requirements) *Failure {
+	available := probe.CPUs()
+	if req.CPUs <= available {
+		return nil
+	}
+	return &Failure{
+		Requirement: "CPU count",
+		Severity:    Warning,
+		Message:     fmt.Sprintf("%d cpus were requested but this host only has %d", req.CPUs, available),
+		Remediation: fmt.Sprintf("Use '--cpus %d' or fewer to avoid overcommitting the host", available),
+	}
+}
+
+func checkMemory(probe Probe, req Requirements) *Failure {
+	if req.MemoryMB <= 0 {
+		return nil
+	}
+	total, available, err := probe.Memory()
+	if err != nil {
+		return nil
+	}
+
+	requested := uint64(req.MemoryMB)
+	if requested > total {
+		return &Failure{
+			Requirement: "Memory",
+			Severity:    Fatal,
+			Message:     fmt.Sprintf("%d MB of memory was requested but this host only has %d MB", requested, total),
+			Remediation: "Use the '--memory' flag to request less memory",
+		}
+	}
+	if requested > available {
+		return &Failure{
+			Requirement: "Memory",
+			Severity:    Warning,
+			Message:     fmt.Sprintf("%d MB of memory was requested but only %d MB is free", requested, available),
+			Remediation: "Close other applications or use the '--memory' flag to request less memory",
+		}
+	}
+	return nil
+}
+
+func checkDisk(probe Probe, dir string, required uint64, severity Severity, remediation string) *Failure {
+	if dir == "" || required == 0 {
+		return nil
+	}
+	free, err := probe.FreeDiskSpace(existingParent(dir))
+	if err != nil || free >= required {
+		return nil
+	}
+	return &Failure{
+		Requirement: "Disk space",
+		Severity:    severity,
+		Message:     fmt.Sprintf("%s needs %d MB of free disk space but only %d MB is available", dir, required>>20, free>>20),
+		Remediation: remediation,
+	}
+}
+
+func checkBinary(probe Probe, binary string) *Failure {
+	if _, err := probe.LookPath(binary); err == nil {
+		return nil
+	}
+	return &Failure{
+		Requirement: "Required binaries",
+		Severity:    Fatal,
+		Message:     fmt.Sprintf("%s could not be found in your PATH", binary),
+		Remediation: binaryRemediation,
+	}
+}
+
+func existingParent(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
@@ -1,5 +1,63 @@
 package host
 
-func (*Host) CheckRequirements() error {
-	return nil
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var requiredBinaries []string
+
+const binaryRemediation = ""
+
+func (h *Host) platformChecks(req Requirements) []Failure {
+	if !req.VM {
+		return nil
+	}
+
+	output, err := exec.Command("sysctl", "-n", "kern.hv_support").Output()
+	if err == nil && strings.TrimSpace(string(output)) == "1" {
+		return nil
+	}
+	return []Failure{{
+		Requirement: "Virtualization",
+		Severity:    Fatal,
+		Message:     "This Mac does not support the Hypervisor framework",
+		Remediation: "CF Dev requires a Mac from 2010 or later running OS X 10.10.3 or later",
+	}}
+}
+
+var vmStatPattern = regexp.MustCompile(`Pages (free|inactive|speculative):\s+(\d+)`)
+
+func (*systemProbe) Memory() (uint64, uint64, error) {
+	output, err := exec.Command("sysctl", "-n", "hw.memsize", "hw.pagesize").Output()
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected sysctl output: %s", output)
+	}
+	total, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	pageSize, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	output, err = exec.Command("vm_stat").Output()
+	if err != nil {
+		return 0, 0, err
+	}
+	var pages uint64
+	for _, match := range vmStatPattern.FindAllStringSubmatch(string(output), -1) {
+		n, _ := strconv.ParseUint(match[2], 10, 64)
+		pages += n
+	}
+
+	return total >> 20, (pages * pageSize) >> 20, nil
 }
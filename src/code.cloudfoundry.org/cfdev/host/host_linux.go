@@ -0,0 +1,150 @@
+package host
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var requiredBinaries = []string{"qemu-system-x86_64", "qemu-img"}
+
+const binaryRemediation = "Install QEMU using your package manager, e.g. 'sudo apt-get install qemu-system-x86 qemu-utils ovmf'"
+
+// lowestForwardedPort is the ssh port qemu forwards on the director address,
+// the http and https ports of the router are the other privileged ones
+const lowestForwardedPort = 22
+
+const capNetBindService = 10
+
+func (h *Host) platformChecks(req Requirements) []Failure {
+	if !req.VM {
+		return nil
+	}
+
+	var failures []Failure
+	if failure := checkKVM(); failure != nil {
+		failures = append(failures, *failure)
+	}
+	if failure := h.checkPrivilegedPorts(); failure != nil {
+		failures = append(failures, *failure)
+	}
+	if failure := checkVMProxy(req.Proxies); failure != nil {
+		failures = append(failures, *failure)
+	}
+	return failures
+}
+
+// checkVMProxy refuses a proxy as nothing in the VM would use it, qemu's user
+// networking connects the VM to the internet directly
+func checkVMProxy(proxies []Proxy) *Failure {
+	if len(proxies) == 0 {
+		return nil
+	}
+	return &Failure{
+		Requirement: "Proxy",
+		Severity:    Fatal,
+		Message:     fmt.Sprintf("The VM cannot reach the internet through the %s %s on linux", proxies[0].Name, proxies[0].URL),
+		Remediation: "Start CF Dev on a network with direct internet access and unset http_proxy and https_proxy",
+	}
+}
+
+func checkKVM() *Failure {
+	f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+	if err == nil {
+		f.Close()
+		return nil
+	}
+
+	remediation := "Enable virtualization (VT-x/AMD-V) in your BIOS and load the kvm module"
+	if os.IsPermission(err) {
+		remediation = "Add your user to the 'kvm' group with 'sudo usermod -aG kvm $USER' and log in again"
+	}
+	return &Failure{
+		Requirement: "Virtualization",
+		Severity:    Warning,
+		Message:     "KVM is not available so the VM will run with much slower software emulation",
+		Remediation: remediation,
+	}
+}
+
+// checkPrivilegedPorts makes sure qemu, which runs as the user, can bind the
+// ssh, http and https ports it forwards on the VM addresses
+func (h *Host) checkPrivilegedPorts() *Failure {
+	if os.Geteuid() == 0 {
+		return nil
+	}
+
+	contents, err := ioutil.ReadFile("/proc/sys/net/ipv4/ip_unprivileged_port_start")
+	if err == nil {
+		if start, err := strconv.Atoi(strings.TrimSpace(string(contents))); err == nil && start <= lowestForwardedPort {
+			return nil
+		}
+	}
+
+	qemu, err := h.probe().LookPath("qemu-system-x86_64")
+	if err != nil {
+		// reported as a missing binary
+		return nil
+	}
+	if canBindPrivilegedPorts(qemu) {
+		return nil
+	}
+
+	return &Failure{
+		Requirement: "Privileged ports",
+		Severity:    Fatal,
+		Message:     fmt.Sprintf("%s cannot bind ports 22, 80 and 443 on the VM addresses as your user", qemu),
+		Remediation: fmt.Sprintf("Allow it with 'sudo setcap cap_net_bind_service=+ep %s', "+
+			"or allow all users to bind them with 'sudo sysctl -w net.ipv4.ip_unprivileged_port_start=%d'", qemu, lowestForwardedPort),
+	}
+}
+
+// canBindPrivilegedPorts reads the file capabilities of the binary, which
+// start with the magic and flags word followed by the permitted set
+func canBindPrivilegedPorts(path string) bool {
+	data := make([]byte, 24)
+	n, err := syscall.Getxattr(path, "security.capability", data)
+	if err != nil || n < 8 {
+		return false
+	}
+
+	const effective = 0x1
+	flags := binary.LittleEndian.Uint32(data[0:4])
+	permitted := binary.LittleEndian.Uint32(data[4:8])
+	return flags&effective != 0 && permitted&(1<<capNetBindService) != 0
+}
+
+func (*systemProbe) Memory() (uint64, uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	values := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		if kb, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[strings.TrimSuffix(fields[0], ":")] = kb
+		}
+	}
+
+	total, ok := values["MemTotal"]
+	if !ok {
+		return 0, 0, fmt.Errorf("unable to read total memory from /proc/meminfo")
+	}
+	available, ok := values["MemAvailable"]
+	if !ok {
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	return total / 1024, available / 1024, nil
+}
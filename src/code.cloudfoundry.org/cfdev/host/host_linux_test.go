@@ -0,0 +1,48 @@
+package host_test
+
+import (
+	"code.cloudfoundry.org/cfdev/host"
+	"code.cloudfoundry.org/cfdev/host/mocks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Host on linux", func() {
+	var (
+		mockController *gomock.Controller
+		mockProbe      *mocks.MockProbe
+		h              *host.Host
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		mockProbe = mocks.NewMockProbe(mockController)
+		mockProbe.EXPECT().CPUs().Return(8).AnyTimes()
+		mockProbe.EXPECT().LookPath(gomock.Any()).Return("/usr/bin/qemu-system-x86_64", nil).AnyTimes()
+		mockProbe.EXPECT().Dial(gomock.Any()).AnyTimes()
+		h = &host.Host{Probe: mockProbe}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("refuses a proxy for the vm", func() {
+		var proxyFailures []host.Failure
+		for _, failure := range h.Check(host.Requirements{
+			VM: true,
+			Proxies: []host.Proxy{
+				{Name: "HTTP proxy", URL: "http://proxy.example.com:8080", Address: "proxy.example.com:8080"},
+			},
+		}) {
+			if failure.Requirement == "Proxy" {
+				proxyFailures = append(proxyFailures, failure)
+			}
+		}
+
+		Expect(proxyFailures).To(HaveLen(1))
+		Expect(proxyFailures[0].Severity).To(Equal(host.Fatal))
+		Expect(proxyFailures[0].Message).To(Equal("The VM cannot reach the internet through the HTTP proxy http://proxy.example.com:8080 on linux"))
+	})
+})
@@ -4,47 +4,92 @@ import (
 	"os/exec"
 	"fmt"
 	"strings"
-	safeerr "code.cloudfoundry.org/cfdev/errors"
-	"errors"
+	"syscall"
+	"unsafe"
 )
 
 const admin_role = "[Security.Principal.WindowsBuiltInRole]::Administrator"
 const current_user = "New-Object Security.Principal.WindowsPrincipal([Security.Principal.WindowsIdentity]::GetCurrent())"
 
-func (*Host) CheckRequirements() error {
-	if err := hasAdminPrivileged(); err != nil {
-		return err
+var requiredBinaries []string
+
+const binaryRemediation = ""
+
+func (h *Host) platformChecks(req Requirements) []Failure {
+	if failure := hasAdminPrivileged(); failure != nil {
+		return []Failure{*failure}
 	}
-	return hypervEnabled()
+	if failure := hypervEnabled(); failure != nil {
+		return []Failure{*failure}
+	}
+	return nil
 }
 
-
-func hasAdminPrivileged() error {
+func hasAdminPrivileged() *Failure {
 	cmd := exec.Command("powershell.exe", "-Command",
 		fmt.Sprintf("(%s).IsInRole(%s)", current_user, admin_role))
 	output, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("checking for admin privileges: %s", err)
+		return &Failure{Requirement: "Admin privileges", Severity: Fatal, Message: fmt.Sprintf("checking for admin privileges: %s", err)}
 	}
 	if strings.TrimSpace(string(output)) == "True" {
 		return nil
 	}
-	return safeerr.SafeWrap(errors.New("You must run cf dev with an admin privileged powershell"),"Running without admin privileges")
+	return &Failure{Requirement: "Running without admin privileges", Severity: Fatal, Message: "You must run cf dev with an admin privileged powershell"}
 }
 
 const hyperv_feature="Get-WindowsOptionalFeature -FeatureName Microsoft-Hyper-V-All -Online"
 const hyperv_disabled_error=`You must first enable Hyper-V on your machine before you run CF Dev. Please use the following tutorial to enable this functionality on your machine
 
 https://docs.microsoft.com/en-us/virtualization/hyper-v-on-windows/quick-start/enable-hyper-v`
-func hypervEnabled() error {
+func hypervEnabled() *Failure {
 	cmd := exec.Command("powershell.exe", "-Command",
 		fmt.Sprintf("(%s).State", hyperv_feature))
 	output, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("checking whether hyperv is enabled: %s", err)
+		return &Failure{Requirement: "Hyper-V", Severity: Fatal, Message: fmt.Sprintf("checking whether hyperv is enabled: %s", err)}
 	}
 	if strings.TrimSpace(string(output)) == "Enabled" {
 		return nil
 	}
-	return safeerr.SafeWrap(errors.New(hyperv_disabled_error),"Hyper-V disabled")
+	return &Failure{Requirement: "Hyper-V disabled", Severity: Fatal, Message: hyperv_disabled_error}
+}
+
+var (
+	kernel32                 = syscall.NewLazyDLL("kernel32.dll")
+	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetDiskFreeSpaceExW  = kernel32.NewProc("GetDiskFreeSpaceExW")
+)
+
+type memoryStatusEx struct {
+	length               uint32
+	memoryLoad           uint32
+	totalPhys            uint64
+	availPhys            uint64
+	totalPageFile        uint64
+	availPageFile        uint64
+	totalVirtual         uint64
+	availVirtual         uint64
+	availExtendedVirtual uint64
+}
+
+func (*systemProbe) Memory() (uint64, uint64, error) {
+	status := memoryStatusEx{}
+	status.length = uint32(unsafe.Sizeof(status))
+	if ret, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status))); ret == 0 {
+		return 0, 0, err
+	}
+	return status.totalPhys >> 20, status.availPhys >> 20, nil
+}
+
+func (*systemProbe) FreeDiskSpace(path string) (uint64, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if ret, _, err := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(pathPtr)), uintptr(unsafe.Pointer(&free)), 0, 0); ret == 0 {
+		return 0, err
+	}
+	return free, nil
 }
\ No newline at end of file
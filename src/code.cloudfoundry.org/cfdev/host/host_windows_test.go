@@ -18,7 +18,7 @@ var _ = Describe("Host", func() {
 				// we assume tests always run on a machine with hyperv enabled
 				It("succeeds", func() {
 					h := &host.Host{}
-					Expect(h.CheckRequirements()).To(Succeed())
+					Expect(h.CheckRequirements(host.Requirements{})).To(Succeed())
 				})
 			})
 			Context("when hyperv is disabled", func() {
@@ -40,7 +40,7 @@ var _ = Describe("Host", func() {
 
 				It("fails", func() {
 					h := &host.Host{}
-				    err := h.CheckRequirements()
+				    err := h.CheckRequirements(host.Requirements{})
 					Expect(err.Error()).To(ContainSubstring(`Hyper-V disabled: You must first enable Hyper-V on your machine`))
 					Expect(errors.SafeError(err)).To(Equal("Hyper-V disabled"))
 				})
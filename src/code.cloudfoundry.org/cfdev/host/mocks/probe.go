@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: code.cloudfoundry.org/cfdev/host (interfaces: Probe)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	host "code.cloudfoundry.org/cfdev/host"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockProbe is a mock of Probe interface
+type MockProbe struct {
+	ctrl     *gomock.Controller
+	recorder *MockProbeMockRecorder
+}
+
+// MockProbeMockRecorder is the mock recorder for MockProbe
+type MockProbeMockRecorder struct {
+	mock *MockProbe
+}
+
+// NewMockProbe creates a new mock instance
+func NewMockProbe(ctrl *gomock.Controller) *MockProbe {
+	mock := &MockProbe{ctrl: ctrl}
+	mock.recorder = &MockProbeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProbe) EXPECT() *MockProbeMockRecorder {
+	return m.recorder
+}
+
+// CPUs mocks base method
+func (m *MockProbe) CPUs() int {
+	ret := m.ctrl.Call(m, "CPUs")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// CPUs indicates an expected call of CPUs
+func (mr *MockProbeMockRecorder) CPUs() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CPUs", reflect.TypeOf((*MockProbe)(nil).CPUs))
+}
+
+// Dial mocks base method
+func (m *MockProbe) Dial(arg0 string) error {
+	ret := m.ctrl.Call(m, "Dial", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dial indicates an expected call of Dial
+func (mr *MockProbeMockRecorder) Dial(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dial", reflect.TypeOf((*MockProbe)(nil).Dial), arg0)
+}
+
+// FreeDiskSpace mocks base method
+func (m *MockProbe) FreeDiskSpace(arg0 string) (uint64, error) {
+	ret := m.ctrl.Call(m, "FreeDiskSpace", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreeDiskSpace indicates an expected call of FreeDiskSpace
+func (mr *MockProbeMockRecorder) FreeDiskSpace(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeDiskSpace", reflect.TypeOf((*MockProbe)(nil).FreeDiskSpace), arg0)
+}
+
+// LookPath mocks base method
+func (m *MockProbe) LookPath(arg0 string) (string, error) {
+	ret := m.ctrl.Call(m, "LookPath", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookPath indicates an expected call of LookPath
+func (mr *MockProbeMockRecorder) LookPath(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookPath", reflect.TypeOf((*MockProbe)(nil).LookPath), arg0)
+}
+
+// Memory mocks base method
+func (m *MockProbe) Memory() (uint64, uint64, error) {
+	ret := m.ctrl.Call(m, "Memory")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Memory indicates an expected call of Memory
+func (mr *MockProbeMockRecorder) Memory() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Memory", reflect.TypeOf((*MockProbe)(nil).Memory))
+}
+
+// Routes mocks base method
+func (m *MockProbe) Routes() ([]host.Route, error) {
+	ret := m.ctrl.Call(m, "Routes")
+	ret0, _ := ret[0].([]host.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Routes indicates an expected call of Routes
+func (mr *MockProbeMockRecorder) Routes() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Routes", reflect.TypeOf((*MockProbe)(nil).Routes))
+}
@@ -0,0 +1,122 @@
+package host
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Network is an address or range the VM needs routed to it from the host.
+type Network struct {
+	Name    string
+	Setting string
+	CIDR    string
+}
+
+// VMNetworks are the addresses and ranges cfdev routes from the host to the VM.
+func VMNetworks(boshDirectorIP, cfRouterIP, containerNetwork string) []Network {
+	return []Network{
+		{Name: "BOSH director IP", Setting: "bosh_director_ip", CIDR: boshDirectorIP},
+		{Name: "router IP", Setting: "router_ip", CIDR: cfRouterIP},
+		{Name: "container network", Setting: "container_network", CIDR: containerNetwork},
+	}
+}
+
+// Route is a network the host already reaches through Interface.
+type Route struct {
+	Interface string
+	Network   net.IPNet
+}
+
+func checkNetworks(probe Probe, networks []Network) []Failure {
+	if len(networks) == 0 {
+		return nil
+	}
+	routes, err := probe.Routes()
+	if err != nil {
+		return []Failure{{
+			Requirement: "Network",
+			Severity:    Warning,
+			Message:     fmt.Sprintf("Unable to inspect the host routes for conflicts: %s", err),
+		}}
+	}
+
+	var failures []Failure
+	for _, network := range networks {
+		wanted, err := parseNetwork(network.CIDR)
+		if err != nil {
+			continue
+		}
+		for _, route := range routes {
+			if ones, _ := route.Network.Mask.Size(); ones == 0 || isOwnAddress(route.Network, networks) {
+				continue
+			}
+			if wanted.Contains(route.Network.IP) || route.Network.Contains(wanted.IP) {
+				failures = append(failures, Failure{
+					Requirement: "Network",
+					Severity:    Fatal,
+					Message: fmt.Sprintf("The %s %s overlaps %s on interface %s",
+						network.Name, network.CIDR, route.Network.String(), route.Interface),
+					Remediation: fmt.Sprintf("Disconnect the VPN or remove the network that owns %s, or set '%s' to a free range in the cfdev config file",
+						route.Interface, network.Setting),
+				})
+				break
+			}
+		}
+	}
+	return failures
+}
+
+// parseNetwork accepts a CIDR or a single address, which is treated as a /32.
+func parseNetwork(value string) (*net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		value += "/32"
+	}
+	_, network, err := net.ParseCIDR(value)
+	return network, err
+}
+
+// isOwnAddress reports whether route is one of the /32 aliases cfdev added
+// itself during a previous start.
+func isOwnAddress(route net.IPNet, networks []Network) bool {
+	if ones, bits := route.Mask.Size(); ones != bits {
+		return false
+	}
+	for _, network := range networks {
+		if net.ParseIP(network.CIDR).Equal(route.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceRoutes returns the networks of the addresses on every interface
+// that is up, ignoring the loopback where cfdev adds its own aliases.
+func interfaceRoutes() ([]Route, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []Route
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			routes = append(routes, Route{
+				Interface: iface.Name,
+				Network:   net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask).To4(), Mask: ipNet.Mask[len(ipNet.Mask)-4:]},
+			})
+		}
+	}
+	return routes, nil
+}
@@ -0,0 +1,16 @@
+package host
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+type systemProbe struct{}
+
+func (*systemProbe) CPUs() int {
+	return runtime.NumCPU()
+}
+
+func (*systemProbe) LookPath(binary string) (string, error) {
+	return exec.LookPath(binary)
+}
@@ -0,0 +1,13 @@
+// +build !windows
+
+package host
+
+import "syscall"
+
+func (*systemProbe) FreeDiskSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,46 @@
+package host
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+const proxyDialTimeout = 5 * time.Second
+
+// Proxy is a proxy the VM reaches the internet through. URL is only used in
+// messages, so it must not hold the password.
+type Proxy struct {
+	Name    string
+	URL     string
+	Address string
+}
+
+func (*systemProbe) Dial(address string) error {
+	conn, err := net.DialTimeout("tcp", address, proxyDialTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func checkProxies(probe Probe, proxies []Proxy) []Failure {
+	var failures []Failure
+	checked := map[string]bool{}
+	for _, proxy := range proxies {
+		if checked[proxy.Address] {
+			continue
+		}
+		checked[proxy.Address] = true
+
+		if err := probe.Dial(proxy.Address); err != nil {
+			failures = append(failures, Failure{
+				Requirement: "Proxy",
+				Severity:    Fatal,
+				Message:     fmt.Sprintf("The %s %s is not reachable: %s", proxy.Name, proxy.URL, err),
+				Remediation: "Check the HTTP_PROXY and HTTPS_PROXY environment variables, or unset them to connect directly",
+			})
+		}
+	}
+	return failures
+}
@@ -0,0 +1,71 @@
+package host
+
+import (
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func (*systemProbe) Routes() ([]Route, error) {
+	routes, err := interfaceRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command("netstat", "-rn", "-f", "inet").Output()
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		// older releases print Refs and Use columns before Netif
+		iface := fields[3]
+		if _, err := strconv.Atoi(iface); err == nil && len(fields) >= 6 {
+			iface = fields[5]
+		}
+		if strings.HasPrefix(iface, "lo") {
+			continue
+		}
+		network, ok := parseNetstatDestination(fields[0])
+		if !ok {
+			continue
+		}
+		routes = append(routes, Route{Interface: iface, Network: network})
+	}
+	return routes, nil
+}
+
+// parseNetstatDestination parses the abbreviated destinations netstat prints,
+// e.g. "10/8", "172.16" or "192.168.1.7".
+func parseNetstatDestination(value string) (net.IPNet, bool) {
+	prefix := -1
+	if i := strings.Index(value, "/"); i >= 0 {
+		p, err := strconv.Atoi(value[i+1:])
+		if err != nil {
+			return net.IPNet{}, false
+		}
+		prefix, value = p, value[:i]
+	}
+
+	octets := strings.Split(value, ".")
+	if len(octets) > 4 {
+		return net.IPNet{}, false
+	}
+	if prefix < 0 {
+		prefix = 8 * len(octets)
+	}
+	for len(octets) < 4 {
+		octets = append(octets, "0")
+	}
+
+	ip := net.ParseIP(strings.Join(octets, ".")).To4()
+	if ip == nil {
+		return net.IPNet{}, false
+	}
+	mask := net.CIDRMask(prefix, 32)
+	return net.IPNet{IP: ip.Mask(mask), Mask: mask}, true
+}
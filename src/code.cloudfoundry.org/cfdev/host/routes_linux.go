@@ -0,0 +1,60 @@
+package host
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+func (*systemProbe) Routes() ([]Route, error) {
+	routes, err := interfaceRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[0] == "lo" {
+			continue
+		}
+		destination, err := parseHexIP(fields[1])
+		if err != nil {
+			continue
+		}
+		mask, err := parseHexIP(fields[7])
+		if err != nil {
+			continue
+		}
+		routes = append(routes, Route{
+			Interface: fields[0],
+			Network:   net.IPNet{IP: destination, Mask: net.IPMask(mask)},
+		})
+	}
+	return routes, scanner.Err()
+}
+
+// parseHexIP decodes the little-endian hex addresses of /proc/net/route.
+func parseHexIP(value string) (net.IP, error) {
+	raw, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != 4 {
+		return nil, fmt.Errorf("unexpected route address: %s", value)
+	}
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+	return ip, nil
+}
@@ -0,0 +1,5 @@
+package host
+
+func (*systemProbe) Routes() ([]Route, error) {
+	return interfaceRoutes()
+}
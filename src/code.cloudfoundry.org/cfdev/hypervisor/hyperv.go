@@ -20,7 +20,7 @@ func (h *HyperV) CreateVM(vm VM) error {
 	if vm.DepsIso == "" {
 		vm.DepsIso = filepath.Join(h.Config.CacheDir, "cf-deps.iso")
 	}
-	var cfDevVHD = filepath.Join(h.Config.CFDevHome, "cfdev.vhd")
+	var cfDevVHD = h.DiskPath(vm.Name)
 
 	cmd := exec.Command("powershell.exe", "-Command", fmt.Sprintf("New-VM -Name %s -Generation 2 -NoVHD", vm.Name))
 	err := cmd.Run()
@@ -94,8 +94,8 @@ func (h *HyperV) CreateVM(vm VM) error {
 	cmd = exec.Command("powershell.exe", "-Command", fmt.Sprintf("Set-VMComPort "+
 		"-VMName %s "+
 		"-number 1 "+
-		"-Path \\\\.\\pipe\\cfdev-com",
-		vm.Name))
+		"-Path \\\\.\\pipe\\%s-com",
+		vm.Name, vm.Name))
 	err = cmd.Run()
 	if err != nil {
 		return fmt.Errorf("setting com port : %s", err)
@@ -115,7 +115,7 @@ func addVhdDrive(isoPath string, vmName string) error {
 }
 
 func (h *HyperV) exists(vmName string) (bool, error) {
-	cmd := exec.Command("powershell.exe", "-Command", fmt.Sprintf("Get-VM -Name %s*", vmName))
+	cmd := exec.Command("powershell.exe", "-Command", fmt.Sprintf("Get-VM -Name %s -ErrorAction SilentlyContinue", vmName))
 	output, err := cmd.Output()
 	if err != nil {
 		return false, fmt.Errorf("getting vms: %s", err)
@@ -169,6 +169,10 @@ func (h *HyperV) Destroy(vmName string) error {
 	return nil
 }
 
+func (h *HyperV) DiskPath(vmName string) string {
+	return filepath.Join(h.Config.EnvDir, vmName+".vhd")
+}
+
 func (h *HyperV) IsRunning(vmName string) (bool, error) {
 	if exists, err := h.exists(vmName); err != nil || !exists {
 		return false, err
@@ -1,12 +1,10 @@
+// +build darwin
+
 package hypervisor
 
 import (
-	"fmt"
 	"io"
-	"os"
-	"path"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"code.cloudfoundry.org/cfdev/config"
@@ -33,6 +31,10 @@ type DaemonRunner interface {
 
 const LinuxKitLabel = "org.cloudfoundry.cfdev.linuxkit"
 
+func (l *LinuxKit) label() string {
+	return l.Config.Label(LinuxKitLabel)
+}
+
 func (l *LinuxKit) CreateVM(vm VM) error {
 	daemonSpec, err := l.DaemonSpec(vm.CPUs, vm.MemoryMB, vm.DepsIso)
 	if err != nil {
@@ -42,17 +44,17 @@ func (l *LinuxKit) CreateVM(vm VM) error {
 }
 
 func (l *LinuxKit) Start(vmName string) error {
-	return l.DaemonRunner.Start(LinuxKitLabel)
+	return l.DaemonRunner.Start(l.label())
 }
 
 func (l *LinuxKit) Stop(vmName string) error {
 	var reterr error
-	if err := l.DaemonRunner.Stop(LinuxKitLabel); err != nil {
+	if err := l.DaemonRunner.Stop(l.label()); err != nil {
 		reterr = err
 	}
 	if err := SafeKill(
-		filepath.Join(l.Config.StateDir, "hyperkit.pid"),
-		"hyperkit",
+		filepath.Join(l.Config.StateDir, vmPidFile),
+		vmProcessName,
 	); err != nil {
 		reterr = err
 	}
@@ -60,65 +62,22 @@ func (l *LinuxKit) Stop(vmName string) error {
 }
 
 func (l *LinuxKit) Destroy(vmName string) error {
-	return l.DaemonRunner.RemoveDaemon(LinuxKitLabel)
+	return l.DaemonRunner.RemoveDaemon(l.label())
 }
 
-func (l *LinuxKit) IsRunning(vmName string) (bool, error) {
-	return l.DaemonRunner.IsRunning(LinuxKitLabel)
+// DiskPath is where linuxkit creates the qcow disk in the state dir
+func (l *LinuxKit) DiskPath(vmName string) string {
+	return filepath.Join(l.Config.StateDir, "disk.qcow2")
 }
 
-func (l *LinuxKit) DaemonSpec(cpus, mem int, depsIsoPath string) (daemon.DaemonSpec, error) {
-	linuxkit := filepath.Join(l.Config.CacheDir, "linuxkit")
-	hyperkit := filepath.Join(l.Config.CacheDir, "hyperkit")
-	uefi := filepath.Join(l.Config.CacheDir, "UEFI.fd")
-	qcowtool := filepath.Join(l.Config.CacheDir, "qcow-tool")
-	vpnkitEthSock := filepath.Join(l.Config.VpnKitStateDir, "vpnkit_eth.sock")
-	vpnkitPortSock := filepath.Join(l.Config.VpnKitStateDir, "vpnkit_port.sock")
-
-	if _, err := os.Stat(depsIsoPath); os.IsNotExist(err) {
-		return daemon.DaemonSpec{}, err
-	}
-
-	osImagePath := filepath.Join(l.Config.CacheDir, "cfdev-efi.iso")
-
-	diskArgs := []string{
-		"type=qcow",
-		"size=80G",
-		"trim=true",
-		fmt.Sprintf("qcow-tool=%s", qcowtool),
-		"qcow-onflush=os",
-		"qcow-compactafter=262144",
-		"qcow-keeperased=262144",
-	}
-
-	return daemon.DaemonSpec{
-		Label:       LinuxKitLabel,
-		Program:     linuxkit,
-		SessionType: "Background",
-		ProgramArguments: []string{
-			linuxkit, "run", "hyperkit",
-			"-console-file",
-			"-cpus", fmt.Sprintf("%d", cpus),
-			"-mem", fmt.Sprintf("%d", mem),
-			"-hyperkit", hyperkit,
-			"-networking", fmt.Sprintf("vpnkit,%v,%v", vpnkitEthSock, vpnkitPortSock),
-			"-fw", uefi,
-			"-disk", strings.Join(diskArgs, ","),
-			"-disk", "file=" + depsIsoPath,
-			"-state", l.Config.StateDir,
-			"--uefi",
-			osImagePath,
-		},
-		RunAtLoad:  false,
-		StdoutPath: path.Join(l.Config.CFDevHome, "linuxkit.stdout.log"),
-		StderrPath: path.Join(l.Config.CFDevHome, "linuxkit.stderr.log"),
-	}, nil
+func (l *LinuxKit) IsRunning(vmName string) (bool, error) {
+	return l.DaemonRunner.IsRunning(l.label())
 }
 
 func (l *LinuxKit) Watch(exit chan string) {
 	go func() {
 		for {
-			running, err := l.DaemonRunner.IsRunning(LinuxKitLabel)
+			running, err := l.DaemonRunner.IsRunning(l.label())
 			if !running && err == nil {
 				exit <- "linuxkit"
 				return
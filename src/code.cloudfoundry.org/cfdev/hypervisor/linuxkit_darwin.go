@@ -0,0 +1,64 @@
+package hypervisor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/daemon"
+)
+
+const (
+	vmPidFile     = "hyperkit.pid"
+	vmProcessName = "hyperkit"
+)
+
+func (l *LinuxKit) DaemonSpec(cpus, mem int, depsIsoPath string) (daemon.DaemonSpec, error) {
+	linuxkit := filepath.Join(l.Config.CacheDir, "linuxkit")
+	hyperkit := filepath.Join(l.Config.CacheDir, "hyperkit")
+	uefi := filepath.Join(l.Config.CacheDir, "UEFI.fd")
+	qcowtool := filepath.Join(l.Config.CacheDir, "qcow-tool")
+	vpnkitEthSock := filepath.Join(l.Config.VpnKitStateDir, "vpnkit_eth.sock")
+	vpnkitPortSock := filepath.Join(l.Config.VpnKitStateDir, "vpnkit_port.sock")
+
+	if _, err := os.Stat(depsIsoPath); os.IsNotExist(err) {
+		return daemon.DaemonSpec{}, err
+	}
+
+	osImagePath := filepath.Join(l.Config.CacheDir, "cfdev-efi.iso")
+
+	diskArgs := []string{
+		"type=qcow",
+		"size=80G",
+		"trim=true",
+		fmt.Sprintf("qcow-tool=%s", qcowtool),
+		"qcow-onflush=os",
+		"qcow-compactafter=262144",
+		"qcow-keeperased=262144",
+	}
+
+	return daemon.DaemonSpec{
+		Label:       l.label(),
+		Program:     linuxkit,
+		SessionType: "Background",
+		ProgramArguments: []string{
+			linuxkit, "run", "hyperkit",
+			"-console-file",
+			"-cpus", fmt.Sprintf("%d", cpus),
+			"-mem", fmt.Sprintf("%d", mem),
+			"-hyperkit", hyperkit,
+			"-networking", fmt.Sprintf("vpnkit,%v,%v", vpnkitEthSock, vpnkitPortSock),
+			"-fw", uefi,
+			"-disk", strings.Join(diskArgs, ","),
+			"-disk", "file=" + depsIsoPath,
+			"-state", l.Config.StateDir,
+			"--uefi",
+			osImagePath,
+		},
+		RunAtLoad:  false,
+		StdoutPath: path.Join(l.Config.EnvDir, "linuxkit.stdout.log"),
+		StderrPath: path.Join(l.Config.EnvDir, "linuxkit.stderr.log"),
+	}, nil
+}
@@ -0,0 +1,263 @@
+// +build !windows
+
+package hypervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/util"
+)
+
+const (
+	qemuBinary      = "qemu-system-x86_64"
+	qemuImgBinary   = "qemu-img"
+	qemuProcessName = "qemu-system"
+	qemuDiskSize    = "80G"
+	qmpRetries      = 10
+)
+
+var firmwareCandidates = []string{
+	"/usr/share/OVMF/OVMF_CODE.fd",
+	"/usr/share/OVMF/OVMF.fd",
+	"/usr/share/ovmf/OVMF.fd",
+	"/usr/share/edk2/ovmf/OVMF_CODE.fd",
+	"/usr/share/qemu/OVMF.fd",
+	"/usr/share/edk2-ovmf/x64/OVMF_CODE.fd",
+}
+
+type QEMU struct {
+	Config    config.Config
+	KVMDevice string
+	Firmware  string
+}
+
+func (q *QEMU) CreateVM(vm VM) error {
+	if vm.DepsIso == "" {
+		vm.DepsIso = filepath.Join(q.Config.CacheDir, "cf-deps.iso")
+	}
+	if _, err := os.Stat(vm.DepsIso); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(q.Config.StateDir, 0755); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(q.DiskPath(vm.Name)); os.IsNotExist(err) {
+		cmd := exec.Command(qemuImgBinary, "create", "-f", "qcow2", q.DiskPath(vm.Name), qemuDiskSize)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("creating qcow2 disk: %s: %s", err, string(output))
+		}
+	}
+
+	contents, err := json.Marshal(vm)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(q.vmPath(), contents, 0644)
+}
+
+func (q *QEMU) Start(vmName string) error {
+	vm, err := q.vm(vmName)
+	if err != nil {
+		return err
+	}
+
+	args, err := q.CommandLine(vm)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(qemuBinary, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("starting qemu: %s: %s", err, string(output))
+	}
+
+	for attempt := 0; ; attempt++ {
+		client, err := DialQMP(q.qmpSocketPath(), time.Second)
+		if err == nil {
+			client.Close()
+			return nil
+		} else if attempt >= qmpRetries {
+			return fmt.Errorf("connecting to qmp socket: %s", err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (q *QEMU) Stop(vmName string) error {
+	if client, err := DialQMP(q.qmpSocketPath(), time.Second); err == nil {
+		client.Execute("quit", nil)
+		client.Close()
+
+		for attempt := 0; attempt < qmpRetries; attempt++ {
+			client, err := DialQMP(q.qmpSocketPath(), time.Second)
+			if err != nil {
+				break
+			}
+			client.Close()
+			time.Sleep(time.Second)
+		}
+	}
+
+	return SafeKill(q.pidPath(), qemuProcessName)
+}
+
+func (q *QEMU) Destroy(vmName string) error {
+	for _, path := range []string{q.DiskPath(vmName), q.vmPath(), q.qmpSocketPath(), q.efiVarsPath()} {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *QEMU) IsRunning(vmName string) (bool, error) {
+	client, err := DialQMP(q.qmpSocketPath(), time.Second)
+	if err != nil {
+		return false, nil
+	}
+	defer client.Close()
+
+	status, err := client.Status()
+	if err != nil {
+		return false, err
+	}
+	return status == "running", nil
+}
+
+func (q *QEMU) CommandLine(vm VM) ([]string, error) {
+	firmware, err := q.firmwareArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	accel, cpu := "tcg", "max"
+	if q.kvmAvailable() {
+		accel, cpu = "kvm", "host"
+	}
+
+	args := []string{
+		"-name", vm.Name,
+		"-machine", "q35,accel=" + accel,
+		"-cpu", cpu,
+		"-smp", fmt.Sprintf("%d", vm.CPUs),
+		"-m", fmt.Sprintf("%d", vm.MemoryMB),
+	}
+	args = append(args, firmware...)
+	return append(args,
+		"-drive", "file="+filepath.Join(q.Config.CacheDir, "cfdev-efi.iso")+",format=raw,media=cdrom,readonly=on",
+		"-drive", "file="+q.DiskPath(vm.Name)+",format=qcow2,if=virtio",
+		"-drive", "file="+vm.DepsIso+",format=raw,if=virtio,readonly=on",
+		"-boot", "d",
+		"-netdev", "user,id=net0"+q.portForwards(),
+		"-device", "virtio-net-pci,netdev=net0",
+		"-display", "none",
+		"-serial", "file:"+filepath.Join(q.Config.StateDir, "console.log"),
+		"-qmp", "unix:"+q.qmpSocketPath()+",server,nowait",
+		"-pidfile", q.pidPath(),
+		"-daemonize",
+	), nil
+}
+
+func (q *QEMU) portForwards() string {
+	forwards := fmt.Sprintf(",hostfwd=tcp:127.0.0.1:%d-:7777", q.Config.GardenPort)
+	for _, port := range []int{25555, 8443, 8844, 22} {
+		forwards += fmt.Sprintf(",hostfwd=tcp:%s:%d-:%d", q.Config.BoshDirectorIP, port, port)
+	}
+	ports := []int{80, 443, 2222}
+	for port := 1024; port <= 1049; port++ {
+		ports = append(ports, port)
+	}
+	for _, port := range ports {
+		forwards += fmt.Sprintf(",hostfwd=tcp:%s:%d-:%d", q.Config.CFRouterIP, port, port)
+	}
+	return forwards
+}
+
+func (q *QEMU) kvmAvailable() bool {
+	device := q.KVMDevice
+	if device == "" {
+		device = "/dev/kvm"
+	}
+	f, err := os.OpenFile(device, os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+func (q *QEMU) firmware() (string, error) {
+	if q.Firmware != "" {
+		return q.Firmware, nil
+	}
+	for _, path := range firmwareCandidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("unable to find UEFI firmware for qemu, please install the OVMF package")
+}
+
+// firmwareArgs boots unified images as bios. Split images only hold the
+// code, they are mapped as flash next to a copy of their variable store.
+func (q *QEMU) firmwareArgs() ([]string, error) {
+	firmware, err := q.firmware()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(filepath.Base(firmware), "_CODE") {
+		return []string{"-bios", firmware}, nil
+	}
+
+	args := []string{"-drive", "if=pflash,format=raw,readonly=on,file=" + firmware}
+	vars := strings.Replace(firmware, "_CODE", "_VARS", 1)
+	if _, err := os.Stat(vars); err != nil {
+		return args, nil
+	}
+	if _, err := os.Stat(q.efiVarsPath()); os.IsNotExist(err) {
+		if err := util.CopyFile(vars, q.efiVarsPath()); err != nil {
+			return nil, fmt.Errorf("copying the uefi variable store: %s", err)
+		}
+	}
+	return append(args, "-drive", "if=pflash,format=raw,file="+q.efiVarsPath()), nil
+}
+
+func (q *QEMU) vm(vmName string) (VM, error) {
+	var vm VM
+	contents, err := ioutil.ReadFile(q.vmPath())
+	if err != nil {
+		return vm, fmt.Errorf("qemu vm with name %s does not exist", vmName)
+	}
+	err = json.Unmarshal(contents, &vm)
+	return vm, err
+}
+
+func (q *QEMU) DiskPath(vmName string) string {
+	return filepath.Join(q.Config.StateDir, vmName+".qcow2")
+}
+
+func (q *QEMU) efiVarsPath() string {
+	return filepath.Join(q.Config.StateDir, "efivars.fd")
+}
+
+func (q *QEMU) vmPath() string {
+	return filepath.Join(q.Config.StateDir, "qemu-vm.json")
+}
+
+func (q *QEMU) pidPath() string {
+	return filepath.Join(q.Config.StateDir, "qemu.pid")
+}
+
+func (q *QEMU) qmpSocketPath() string {
+	return filepath.Join(q.Config.StateDir, "qmp.sock")
+}
@@ -0,0 +1,320 @@
+// +build !windows
+
+package hypervisor_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/hypervisor"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeQMPServer struct {
+	listener  net.Listener
+	responses map[string]string
+	commands  chan string
+}
+
+func newFakeQMPServer(socketPath string, responses map[string]string) *fakeQMPServer {
+	listener, err := net.Listen("unix", socketPath)
+	Expect(err).NotTo(HaveOccurred())
+
+	server := &fakeQMPServer{
+		listener:  listener,
+		responses: responses,
+		commands:  make(chan string, 10),
+	}
+	go server.serve()
+	return server
+}
+
+func (s *fakeQMPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			fmt.Fprintln(conn, `{"QMP": {"version": {"qemu": {"micro": 0, "minor": 11, "major": 2}}, "capabilities": []}}`)
+
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				var command struct {
+					Execute string `json:"execute"`
+				}
+				json.Unmarshal(scanner.Bytes(), &command)
+				s.commands <- command.Execute
+
+				fmt.Fprintln(conn, `{"event": "SOME_EVENT", "data": {}}`)
+				if response, ok := s.responses[command.Execute]; ok {
+					fmt.Fprintln(conn, response)
+				} else {
+					fmt.Fprintln(conn, `{"return": {}}`)
+				}
+			}
+		}(conn)
+	}
+}
+
+func (s *fakeQMPServer) Close() {
+	s.listener.Close()
+}
+
+var _ = Describe("QEMU", func() {
+	var (
+		qemu     *hypervisor.QEMU
+		stateDir string
+		cacheDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "qemu-state")
+		Expect(err).NotTo(HaveOccurred())
+		cacheDir, err = ioutil.TempDir("", "qemu-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		qemu = &hypervisor.QEMU{
+			Config: config.Config{
+				BoshDirectorIP: "10.245.0.2",
+				CFRouterIP:     "10.144.0.34",
+				GardenPort:     8888,
+				StateDir:       stateDir,
+				CacheDir:       cacheDir,
+			},
+			KVMDevice: filepath.Join(stateDir, "no-kvm-here"),
+			Firmware:  "/some/OVMF.fd",
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+		os.RemoveAll(cacheDir)
+	})
+
+	Describe("CommandLine", func() {
+		vm := hypervisor.VM{Name: "cfdev", CPUs: 4, MemoryMB: 4096, DepsIso: "/some/cf-deps.iso"}
+
+		It("boots the efi iso with the deps iso and a qcow2 data disk", func() {
+			args, err := qemu.CommandLine(vm)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(args).To(ContainElement("file=" + filepath.Join(cacheDir, "cfdev-efi.iso") + ",format=raw,media=cdrom,readonly=on"))
+			Expect(args).To(ContainElement("file=" + filepath.Join(stateDir, "cfdev.qcow2") + ",format=qcow2,if=virtio"))
+			Expect(args).To(ContainElement("file=/some/cf-deps.iso,format=raw,if=virtio,readonly=on"))
+			Expect(args).To(ContainElement("-bios"))
+			Expect(args).To(ContainElement("/some/OVMF.fd"))
+			Expect(args).To(ContainElement("4"))
+			Expect(args).To(ContainElement("4096"))
+		})
+
+		It("names the disk after the vm", func() {
+			args, err := qemu.CommandLine(hypervisor.VM{Name: "cfdev-other", DepsIso: "/some/cf-deps.iso"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(args).To(ContainElement("file=" + filepath.Join(stateDir, "cfdev-other.qcow2") + ",format=qcow2,if=virtio"))
+		})
+
+		Context("when the firmware is a split image", func() {
+			var firmwareDir string
+
+			BeforeEach(func() {
+				var err error
+				firmwareDir, err = ioutil.TempDir("", "qemu-firmware")
+				Expect(err).NotTo(HaveOccurred())
+				qemu.Firmware = filepath.Join(firmwareDir, "OVMF_CODE.fd")
+				Expect(ioutil.WriteFile(qemu.Firmware, []byte("code"), 0644)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(firmwareDir, "OVMF_VARS.fd"), []byte("vars"), 0644)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(firmwareDir)
+			})
+
+			It("maps the code and a copy of the variable store as flash", func() {
+				args, err := qemu.CommandLine(vm)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(args).NotTo(ContainElement("-bios"))
+				Expect(args).To(ContainElement("if=pflash,format=raw,readonly=on,file=" + qemu.Firmware))
+				Expect(args).To(ContainElement("if=pflash,format=raw,file=" + filepath.Join(stateDir, "efivars.fd")))
+				Expect(ioutil.ReadFile(filepath.Join(stateDir, "efivars.fd"))).To(Equal([]byte("vars")))
+			})
+		})
+
+		It("manages the vm through a qmp socket and pidfile in the state dir", func() {
+			args, err := qemu.CommandLine(vm)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(args).To(ContainElement("unix:" + filepath.Join(stateDir, "qmp.sock") + ",server,nowait"))
+			Expect(args).To(ContainElement(filepath.Join(stateDir, "qemu.pid")))
+			Expect(args).To(ContainElement("-daemonize"))
+		})
+
+		It("forwards garden, bosh and router ports", func() {
+			args, err := qemu.CommandLine(vm)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(args).To(ContainElement(SatisfyAll(
+				ContainSubstring("hostfwd=tcp:127.0.0.1:8888-:7777"),
+				ContainSubstring("hostfwd=tcp:10.245.0.2:25555-:25555"),
+				ContainSubstring("hostfwd=tcp:10.144.0.34:443-:443"),
+				ContainSubstring("hostfwd=tcp:10.144.0.34:1049-:1049"),
+			)))
+		})
+
+		It("forwards garden on the port of the environment", func() {
+			qemu.Config.GardenPort = 8890
+
+			args, err := qemu.CommandLine(vm)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(args).To(ContainElement(ContainSubstring("hostfwd=tcp:127.0.0.1:8890-:7777")))
+		})
+
+		Context("when kvm is not available", func() {
+			It("falls back to tcg emulation", func() {
+				args, err := qemu.CommandLine(vm)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(args).To(ContainElement("q35,accel=tcg"))
+				Expect(args).To(ContainElement("max"))
+			})
+		})
+
+		Context("when kvm is available", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(qemu.KVMDevice, []byte{}, 0600)).To(Succeed())
+			})
+
+			It("uses kvm acceleration", func() {
+				args, err := qemu.CommandLine(vm)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(args).To(ContainElement("q35,accel=kvm"))
+				Expect(args).To(ContainElement("host"))
+			})
+		})
+	})
+
+	Describe("IsRunning", func() {
+		Context("when the qmp socket does not exist", func() {
+			It("returns false", func() {
+				Expect(qemu.IsRunning("cfdev")).To(BeFalse())
+			})
+		})
+
+		Context("when qemu reports the vm as running", func() {
+			It("returns true", func() {
+				server := newFakeQMPServer(filepath.Join(stateDir, "qmp.sock"), map[string]string{
+					"query-status": `{"return": {"status": "running", "singlestep": false, "running": true}}`,
+				})
+				defer server.Close()
+
+				Expect(qemu.IsRunning("cfdev")).To(BeTrue())
+				Expect(server.commands).To(Receive(Equal("qmp_capabilities")))
+				Expect(server.commands).To(Receive(Equal("query-status")))
+			})
+		})
+
+		Context("when qemu reports the vm as shutdown", func() {
+			It("returns false", func() {
+				server := newFakeQMPServer(filepath.Join(stateDir, "qmp.sock"), map[string]string{
+					"query-status": `{"return": {"status": "shutdown", "singlestep": false, "running": false}}`,
+				})
+				defer server.Close()
+
+				Expect(qemu.IsRunning("cfdev")).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Stop", func() {
+		It("asks qemu to quit over qmp", func() {
+			server := newFakeQMPServer(filepath.Join(stateDir, "qmp.sock"), nil)
+
+			go func() {
+				defer GinkgoRecover()
+				Eventually(server.commands).Should(Receive(Equal("quit")))
+				server.Close()
+			}()
+
+			Expect(qemu.Stop("cfdev")).To(Succeed())
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("removes the disk and vm state", func() {
+			Expect(ioutil.WriteFile(filepath.Join(stateDir, "cfdev.qcow2"), []byte{}, 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(stateDir, "qemu-vm.json"), []byte("{}"), 0644)).To(Succeed())
+
+			Expect(qemu.Destroy("cfdev")).To(Succeed())
+
+			Expect(filepath.Join(stateDir, "cfdev.qcow2")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(stateDir, "qemu-vm.json")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("Start", func() {
+		Context("when the vm was never created", func() {
+			It("returns an error", func() {
+				Expect(qemu.Start("cfdev")).To(MatchError("qemu vm with name cfdev does not exist"))
+			})
+		})
+	})
+})
+
+var _ = Describe("QMPClient", func() {
+	var (
+		socketPath string
+		tmpDir     string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "qmp")
+		Expect(err).NotTo(HaveOccurred())
+		socketPath = filepath.Join(tmpDir, "qmp.sock")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("negotiates capabilities and skips asynchronous events", func() {
+		server := newFakeQMPServer(socketPath, map[string]string{
+			"query-status": `{"return": {"status": "paused", "running": false}}`,
+		})
+		defer server.Close()
+
+		client, err := hypervisor.DialQMP(socketPath, time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		defer client.Close()
+
+		Expect(client.Status()).To(Equal("paused"))
+	})
+
+	It("returns qmp errors", func() {
+		server := newFakeQMPServer(socketPath, map[string]string{
+			"some-command": `{"error": {"class": "CommandNotFound", "desc": "The command some-command has not been found"}}`,
+		})
+		defer server.Close()
+
+		client, err := hypervisor.DialQMP(socketPath, time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		defer client.Close()
+
+		_, err = client.Execute("some-command", nil)
+		Expect(err).To(MatchError("qmp some-command: CommandNotFound: The command some-command has not been found"))
+	})
+})
@@ -0,0 +1,102 @@
+// +build !windows
+
+package hypervisor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+type QMPClient struct {
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+type qmpCommand struct {
+	Execute   string      `json:"execute"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+type qmpResponse struct {
+	QMP    *json.RawMessage `json:"QMP"`
+	Event  string           `json:"event"`
+	Return *json.RawMessage `json:"return"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+}
+
+func DialQMP(socketPath string, timeout time.Duration) (*QMPClient, error) {
+	conn, err := net.DialTimeout("unix", socketPath, timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client := &QMPClient{
+		conn:    conn,
+		decoder: json.NewDecoder(bufio.NewReader(conn)),
+	}
+
+	var greeting qmpResponse
+	if err := client.decoder.Decode(&greeting); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("reading qmp greeting: %s", err)
+	}
+	if greeting.QMP == nil {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected qmp greeting")
+	}
+
+	if _, err := client.Execute("qmp_capabilities", nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *QMPClient) Execute(command string, arguments interface{}) (json.RawMessage, error) {
+	if err := json.NewEncoder(c.conn).Encode(qmpCommand{Execute: command, Arguments: arguments}); err != nil {
+		return nil, err
+	}
+
+	for {
+		var resp qmpResponse
+		if err := c.decoder.Decode(&resp); err != nil {
+			return nil, fmt.Errorf("reading qmp response to %s: %s", command, err)
+		}
+		if resp.Event != "" {
+			continue
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("qmp %s: %s: %s", command, resp.Error.Class, resp.Error.Desc)
+		}
+		if resp.Return == nil {
+			return json.RawMessage("{}"), nil
+		}
+		return *resp.Return, nil
+	}
+}
+
+func (c *QMPClient) Status() (string, error) {
+	ret, err := c.Execute("query-status", nil)
+	if err != nil {
+		return "", err
+	}
+	var status struct {
+		Status  string `json:"status"`
+		Running bool   `json:"running"`
+	}
+	if err := json.Unmarshal(ret, &status); err != nil {
+		return "", err
+	}
+	return status.Status, nil
+}
+
+func (c *QMPClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,126 @@
+package hypervisor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var SafeKillTimeout = 10 * time.Second
+
+const clockTicksPerSecond = 100
+
+func SafeKill(pidfile, name string) error {
+	info, err := os.Stat(pidfile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(pidfile)
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return os.Remove(pidfile)
+	}
+
+	if isProcess(pid, name, info.ModTime()) {
+		syscall.Kill(pid, syscall.SIGTERM)
+		if !waitForExit(pid, name, SafeKillTimeout) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			if !waitForExit(pid, name, SafeKillTimeout) {
+				return fmt.Errorf("process %d did not exit after SIGKILL", pid)
+			}
+		}
+	}
+
+	if err := os.Remove(pidfile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func isProcess(pid int, name string, pidfileWritten time.Time) bool {
+	path, err := executablePath(pid)
+	if err != nil || path == "" {
+		return false
+	}
+	if !strings.Contains(filepath.Base(path), name) {
+		return false
+	}
+
+	started, err := processStartTime(pid)
+	if err != nil {
+		return false
+	}
+	// a process started after its pidfile was written has recycled the pid
+	return !started.After(pidfileWritten.Add(time.Second))
+}
+
+func waitForExit(pid int, name string, timeout time.Duration) bool {
+	for start := time.Now(); time.Since(start) < timeout; time.Sleep(100 * time.Millisecond) {
+		if path, _ := executablePath(pid); !strings.Contains(filepath.Base(path), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func executablePath(pid int) (string, error) {
+	path, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return strings.TrimSuffix(path, " (deleted)"), err
+}
+
+func processStartTime(pid int) (time.Time, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// the command name field may contain spaces, so parse after its closing paren
+	fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+	if len(fields) < 20 {
+		return time.Time{}, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	ticks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	boot, err := bootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return boot.Add(time.Duration(ticks) * time.Second / clockTicksPerSecond), nil
+}
+
+func bootTime() (time.Time, error) {
+	stat, err := ioutil.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(stat), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "btime" {
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to determine boot time")
+}
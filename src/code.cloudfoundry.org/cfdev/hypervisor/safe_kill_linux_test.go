@@ -0,0 +1,124 @@
+package hypervisor_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/cfdev/hypervisor"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("SafeKill", func() {
+	var (
+		err           error
+		processToKill *gexec.Session
+		tmpDir        string
+		pidFile       string
+	)
+
+	startProcess := func(cmd *exec.Cmd) {
+		processToKill, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(pidFile, []byte(strconv.Itoa(processToKill.Command.Process.Pid)), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		tmpDir, err = ioutil.TempDir("", "safe-kill-test")
+		Expect(err).NotTo(HaveOccurred())
+		pidFile = filepath.Join(tmpDir, "processToKill.pid")
+		hypervisor.SafeKillTimeout = time.Second
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		gexec.KillAndWait()
+		hypervisor.SafeKillTimeout = 10 * time.Second
+	})
+
+	Context("process is still running", func() {
+		BeforeEach(func() {
+			startProcess(exec.Command("sleep", "36000"))
+		})
+
+		It("terminates the process and cleans up the pidfile", func() {
+			Expect(hypervisor.SafeKill(pidFile, "sleep")).To(Succeed())
+			Eventually(processToKill).Should(gexec.Exit(128 + int(syscall.SIGTERM)))
+			Expect(pidFile).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("process ignores SIGTERM", func() {
+		BeforeEach(func() {
+			startProcess(exec.Command("sh", "-c", `trap "" TERM; while true; do sleep 0.1; done`))
+		})
+
+		It("kills the process after the timeout", func() {
+			Expect(hypervisor.SafeKill(pidFile, "sh")).To(Succeed())
+			Eventually(processToKill).Should(gexec.Exit())
+			Expect(pidFile).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("process is still running with different filename", func() {
+		BeforeEach(func() {
+			startProcess(exec.Command("sleep", "36000"))
+		})
+
+		It("leaves process running and cleans up the pidfile", func() {
+			Expect(hypervisor.SafeKill(pidFile, "other")).To(Succeed())
+			Expect(pidFile).NotTo(BeAnExistingFile())
+			Consistently(processToKill).ShouldNot(gexec.Exit())
+		})
+	})
+
+	Context("pid was recycled by a process started after the pidfile was written", func() {
+		BeforeEach(func() {
+			startProcess(exec.Command("sleep", "36000"))
+			anHourAgo := time.Now().Add(-time.Hour)
+			Expect(os.Chtimes(pidFile, anHourAgo, anHourAgo)).To(Succeed())
+		})
+
+		It("leaves process running and cleans up the pidfile", func() {
+			Expect(hypervisor.SafeKill(pidFile, "sleep")).To(Succeed())
+			Expect(pidFile).NotTo(BeAnExistingFile())
+			Consistently(processToKill).ShouldNot(gexec.Exit())
+		})
+	})
+
+	Context("process is no longer running", func() {
+		BeforeEach(func() {
+			startProcess(exec.Command("sleep", "36000"))
+			gexec.KillAndWait()
+			Expect(processToKill).To(gexec.Exit())
+		})
+
+		It("cleans up the pidfile", func() {
+			Expect(hypervisor.SafeKill(pidFile, "sleep")).To(Succeed())
+			Expect(pidFile).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("pidfile does not contain a pid", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(pidFile, []byte("garbage"), 0644)).To(Succeed())
+		})
+
+		It("cleans up the pidfile", func() {
+			Expect(hypervisor.SafeKill(pidFile, "sleep")).To(Succeed())
+			Expect(pidFile).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("pidfile does not exist", func() {
+		It("succeeds", func() {
+			Expect(hypervisor.SafeKill(pidFile, "sleep")).To(Succeed())
+		})
+	})
+})
@@ -1,12 +1,16 @@
 package main
 
 import _ "code.cloudfoundry.org/cfdev/unset-bosh-all-proxy"
+import _ "code.cloudfoundry.org/cfdev/daemon/supervise"
+import _ "code.cloudfoundry.org/cfdev/network/alias"
 import (
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"syscall"
 
@@ -32,6 +36,7 @@ type Plugin struct {
 	Config    config.Config
 	Analytics *cfanalytics.Analytics
 	Root      *cobra.Command
+	NewRoot   func(config.Config) *cobra.Command
 	Version   plugin.VersionType
 }
 
@@ -67,12 +72,17 @@ func main() {
 	analyticsClient := cfanalytics.New(analyticsToggle, baseAnalyticsClient, conf.CliVersion.Original, exitChan, ui)
 	defer analyticsClient.Close()
 
+	newRoot := func(conf config.Config) *cobra.Command {
+		return cmd.NewRoot(exitChan, ui, conf, analyticsClient, analyticsToggle)
+	}
+
 	v := conf.CliVersion
 	cfdev := &Plugin{
 		UI:        ui,
 		Config:    conf,
 		Analytics: analyticsClient,
-		Root:      cmd.NewRoot(exitChan, ui, conf, analyticsClient, analyticsToggle),
+		Root:      newRoot(conf),
+		NewRoot:   newRoot,
 		Version:   plugin.VersionType{Major: v.Major, Minor: v.Minor, Build: v.Build},
 	}
 
@@ -109,6 +119,18 @@ func (p *Plugin) Run(connection plugin.CliConnection, args []string) {
 		}
 	}
 
+	if name := envName(args); name != "" {
+		conf, err := p.Config.ForEnv(name)
+		if err == nil && conf.Env != "" && !config.NamedEnvs {
+			err = fmt.Errorf("named environments are not supported on %s yet, leave out --env and CFDEV_ENV", runtime.GOOS)
+		}
+		if err != nil {
+			p.UI.Failed(err.Error())
+			os.Exit(1)
+		}
+		p.Root = p.NewRoot(conf)
+	}
+
 	p.Root.SetArgs(args)
 	if err := p.Root.Execute(); err != nil {
 		p.UI.Failed(err.Error())
@@ -118,3 +140,16 @@ func (p *Plugin) Run(connection plugin.CliConnection, args []string) {
 		os.Exit(1)
 	}
 }
+
+// envName finds the environment selected with the global --env flag or the
+// CFDEV_ENV variable, which has to be known before the commands are built.
+func envName(args []string) string {
+	for i, arg := range args {
+		if arg == "--env" && i+1 < len(args) {
+			return args[i+1]
+		} else if strings.HasPrefix(arg, "--env=") {
+			return strings.TrimPrefix(arg, "--env=")
+		}
+	}
+	return os.Getenv("CFDEV_ENV")
+}
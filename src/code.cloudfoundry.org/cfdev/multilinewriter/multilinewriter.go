@@ -0,0 +1,43 @@
+package multilinewriter
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Writer keeps one line per key and redraws all of them in place whenever
+// one changes, so the progress of concurrent tasks can be shown together.
+type Writer struct {
+	w     io.Writer
+	keys  []string
+	lines map[string]string
+	drawn int
+	mu    sync.Mutex
+}
+
+func New(w io.Writer) *Writer {
+	return &Writer{w: w, lines: map[string]string{}}
+}
+
+// Say sets the line of key, adding it below the others the first time.
+func (w *Writer) Say(key string, message string, args ...interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.lines[key]; !ok {
+		w.keys = append(w.keys, key)
+	}
+	w.lines[key] = fmt.Sprintf(message, args...)
+	w.redraw()
+}
+
+func (w *Writer) redraw() {
+	if w.drawn > 0 {
+		fmt.Fprintf(w.w, "\033[%dA", w.drawn)
+	}
+	for _, key := range w.keys {
+		fmt.Fprintf(w.w, "\r\033[K%s\n", w.lines[key])
+	}
+	w.drawn = len(w.keys)
+}
@@ -0,0 +1,13 @@
+package multilinewriter_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMultilinewriter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Multilinewriter Suite")
+}
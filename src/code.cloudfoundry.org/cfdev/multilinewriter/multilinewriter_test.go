@@ -0,0 +1,34 @@
+package multilinewriter_test
+
+import (
+	"bytes"
+
+	"code.cloudfoundry.org/cfdev/multilinewriter"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MultiLineWriter", func() {
+	var (
+		buf     bytes.Buffer
+		subject *multilinewriter.Writer
+	)
+	BeforeEach(func() {
+		buf.Reset()
+		subject = multilinewriter.New(&buf)
+	})
+
+	It("writes a new line for every key", func() {
+		subject.Say("mysql", "  %s: %s", "Mysql", "waiting")
+		Expect(buf.String()).To(Equal("\r\033[K  Mysql: waiting\n"))
+	})
+
+	It("redraws every line in place when one changes", func() {
+		subject.Say("mysql", "Mysql: waiting")
+		subject.Say("redis", "Redis: waiting")
+		buf.Reset()
+
+		subject.Say("mysql", "Mysql: done")
+		Expect(buf.String()).To(Equal("\033[2A\r\033[KMysql: done\n\r\033[KRedis: waiting\n"))
+	})
+})
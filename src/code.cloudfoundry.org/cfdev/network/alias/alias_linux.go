@@ -0,0 +1,23 @@
+package alias
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"code.cloudfoundry.org/cfdev/network"
+)
+
+func init() {
+	action := os.Getenv(network.AliasActionEnv)
+	if action == "" {
+		return
+	}
+
+	addrs := strings.Split(os.Getenv(network.AliasAddrsEnv), ",")
+	if err := network.ApplyAliases(action, os.Getenv(network.AliasInterfaceEnv), addrs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
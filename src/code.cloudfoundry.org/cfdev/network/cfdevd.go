@@ -6,10 +6,12 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"strings"
 )
 
 type CFDevD struct {
 	ExecutablePath string
+	AliasIPs       []string
 }
 
 func IsCFDevDInstalled(sockPath string, binPath string, expectedMD5 string) bool {
@@ -33,7 +35,11 @@ func IsCFDevDInstalled(sockPath string, binPath string, expectedMD5 string) bool
 
 func (c *CFDevD) Install() error {
 	fmt.Println("Installing networking components (requires root privileges)")
-	cmd := exec.Command("sudo", "-S", c.ExecutablePath, "install")
+	args := []string{"-S", c.ExecutablePath, "install"}
+	if len(c.AliasIPs) > 0 {
+		args = append(args, "--alias-ips", strings.Join(c.AliasIPs, ","))
+	}
+	cmd := exec.Command("sudo", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
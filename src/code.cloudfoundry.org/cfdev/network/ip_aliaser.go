@@ -9,5 +9,7 @@ type CfdevdClient interface {
 
 type HostNet struct{
 	CfdevdClient CfdevdClient
+	Interface    string
+	Switch       string
 }
 
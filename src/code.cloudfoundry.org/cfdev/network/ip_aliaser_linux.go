@@ -0,0 +1,186 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+const loopback = "lo"
+
+const (
+	AliasActionEnv    = "CFDEV_ALIAS_ACTION"
+	AliasInterfaceEnv = "CFDEV_ALIAS_INTERFACE"
+	AliasAddrsEnv     = "CFDEV_ALIAS_ADDRS"
+)
+
+func (h *HostNet) RemoveLoopbackAliases(addrs ...string) error {
+	iface := h.iface()
+	present, err := filterAliases(iface, addrs, true)
+	if err != nil {
+		return err
+	}
+	if len(present) == 0 && (iface == loopback || !linkExists(iface)) {
+		return nil
+	}
+
+	return h.apply("remove", iface, present)
+}
+
+func (h *HostNet) AddLoopbackAliases(addrs ...string) error {
+	iface := h.iface()
+	missing, err := filterAliases(iface, addrs, false)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	fmt.Println("Setting up IP aliases for the BOSH Director & CF Router (requires administrator privileges)")
+	return h.apply("add", iface, missing)
+}
+
+func (h *HostNet) iface() string {
+	if h.Interface == "" {
+		return loopback
+	}
+	return h.Interface
+}
+
+func (h *HostNet) apply(action, iface string, addrs []string) error {
+	err := ApplyAliases(action, iface, addrs)
+	if err != syscall.EPERM || os.Geteuid() == 0 {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sudo", "-S", "env",
+		AliasActionEnv+"="+action,
+		AliasInterfaceEnv+"="+iface,
+		AliasAddrsEnv+"="+strings.Join(addrs, ","),
+		executable,
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s aliases %s: %s", action, strings.Join(addrs, ", "), err)
+	}
+	return nil
+}
+
+// ApplyAliases adds or removes /32 addresses on iface. Interfaces other than
+// the loopback are created as dummy links on add and deleted once empty.
+func ApplyAliases(action, iface string, addrs []string) error {
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr).To4()
+		if ip == nil {
+			return fmt.Errorf("invalid IPv4 address: %s", addr)
+		}
+		ips = append(ips, ip)
+	}
+
+	conn, err := dialRtnetlink()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch action {
+	case "add":
+		if iface != loopback && !linkExists(iface) {
+			if err := conn.addDummyLink(iface); err != nil && err != syscall.EEXIST {
+				if err == syscall.EPERM {
+					return err
+				}
+				return fmt.Errorf("creating dummy interface %s: %s", iface, err)
+			}
+		}
+		index, err := linkIndex(iface)
+		if err != nil {
+			return err
+		}
+		for _, ip := range ips {
+			if err := conn.addAddr(index, ip); err != nil && err != syscall.EEXIST {
+				return err
+			}
+		}
+	case "remove":
+		index, err := linkIndex(iface)
+		if err != nil {
+			return nil
+		}
+		for _, ip := range ips {
+			if err := conn.delAddr(index, ip); err != nil && err != syscall.EADDRNOTAVAIL {
+				return err
+			}
+		}
+		if iface != loopback {
+			if remaining, err := ifaceAddrs(iface); err == nil && len(remaining) == 0 {
+				return conn.delLink(index)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown alias action: %s", action)
+	}
+	return nil
+}
+
+func filterAliases(iface string, addrs []string, present bool) ([]string, error) {
+	existing, err := ifaceAddrs(iface)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []string
+	for _, addr := range addrs {
+		if existing[addr] == present {
+			filtered = append(filtered, addr)
+		}
+	}
+	return filtered, nil
+}
+
+func ifaceAddrs(iface string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if !linkExists(iface) {
+		return existing, nil
+	}
+
+	i, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, fmt.Errorf("getting interface %s: %s", iface, err)
+	}
+	addrs, err := i.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("getting interface addrs: %s", err)
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+			existing[ipnet.IP.String()] = true
+		}
+	}
+	return existing, nil
+}
+
+func linkExists(iface string) bool {
+	_, err := linkIndex(iface)
+	return err == nil
+}
+
+func linkIndex(iface string) (int, error) {
+	i, err := net.InterfaceByName(iface)
+	if err != nil {
+		return 0, err
+	}
+	return i.Index, nil
+}
@@ -7,10 +7,15 @@ import (
 	"time"
 )
 
-const loopback = "vEthernet (cfdev)"
+func (h *HostNet) switchName() string {
+	if h.Switch == "" {
+		return "cfdev"
+	}
+	return h.Switch
+}
 
 func (h *HostNet) RemoveLoopbackAliases(addrs ...string) error {
-	exists, err := switchExists()
+	exists, err := switchExists(h.switchName())
 	if err != nil {
 		return err
 	}
@@ -18,14 +23,14 @@ func (h *HostNet) RemoveLoopbackAliases(addrs ...string) error {
 		return nil
 	}
 
-	command := exec.Command("powershell.exe", "-Command", "Remove-VMSwitch -Name cfdev -force")
+	command := exec.Command("powershell.exe", "-Command", fmt.Sprintf("Remove-VMSwitch -Name %s -force", h.switchName()))
 	return command.Run()
 }
 
 func (h *HostNet) AddLoopbackAliases(addrs ...string) error {
 	fmt.Println("Setting up IP aliases for the BOSH Director & CF Router (requires administrator privileges)")
 
-	if err := createInterface(); err != nil {
+	if err := createSwitchIfNotExist(h.switchName()); err != nil {
 		return err
 	}
 
@@ -40,7 +45,7 @@ func (h *HostNet) AddLoopbackAliases(addrs ...string) error {
 			continue
 		}
 
-		err = addAlias(addr)
+		err = addAlias(h.switchName(), addr)
 		if err != nil {
 			return err
 		}
@@ -48,7 +53,8 @@ func (h *HostNet) AddLoopbackAliases(addrs ...string) error {
 	return nil
 }
 
-func addAlias(alias string) error {
+func addAlias(switchName, alias string) error {
+	loopback := fmt.Sprintf("vEthernet (%s)", switchName)
 	cmd := exec.Command("netsh", "interface", "ip", "add", "address", loopback, alias, "255.255.255.255")
 
 	if err := cmd.Run(); err != nil {
@@ -58,10 +64,6 @@ func addAlias(alias string) error {
 	return waitForAlias(alias)
 }
 
-func createInterface() error {
-	return createSwitchIfNotExist()
-}
-
 func aliasExists(alias string) (bool, error) {
 	command := exec.Command("powershell.exe", "-Command", "ipconfig")
 	output, err := command.Output()
@@ -72,8 +74,8 @@ func aliasExists(alias string) (bool, error) {
 	return strings.Contains(string(output), alias), nil
 }
 
-func createSwitchIfNotExist() error {
-	exists, err := switchExists()
+func createSwitchIfNotExist(name string) error {
+	exists, err := switchExists(name)
 	if err != nil {
 		return err
 	}
@@ -81,12 +83,12 @@ func createSwitchIfNotExist() error {
 		return nil
 	}
 
-	command := exec.Command("powershell.exe", "-Command", "New-VMSwitch -Name cfdev -SwitchType Internal -Notes 'Switch for CF Dev Networking'")
+	command := exec.Command("powershell.exe", "-Command", fmt.Sprintf("New-VMSwitch -Name %s -SwitchType Internal -Notes 'Switch for CF Dev Networking'", name))
 	return command.Run()
 }
 
-func switchExists() (bool, error) {
-	command := exec.Command("powershell.exe", "-Command", "Get-VMSwitch cfdev*")
+func switchExists(name string) (bool, error) {
+	command := exec.Command("powershell.exe", "-Command", fmt.Sprintf("Get-VMSwitch -Name %s -ErrorAction SilentlyContinue", name))
 	output, err := command.Output()
 	if err != nil {
 		return false, err
@@ -0,0 +1,130 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	iflaInfoKind = 1
+	nlmsgAlignTo = 4
+)
+
+type rtnetlink struct {
+	fd  int
+	seq uint32
+}
+
+func dialRtnetlink() (*rtnetlink, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	return &rtnetlink{fd: fd}, nil
+}
+
+func (r *rtnetlink) Close() error {
+	return syscall.Close(r.fd)
+}
+
+func (r *rtnetlink) addAddr(index int, ip net.IP) error {
+	return r.request(syscall.RTM_NEWADDR, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL, addrMessage(index, ip))
+}
+
+func (r *rtnetlink) delAddr(index int, ip net.IP) error {
+	return r.request(syscall.RTM_DELADDR, 0, addrMessage(index, ip))
+}
+
+func (r *rtnetlink) addDummyLink(name string) error {
+	msg := syscall.IfInfomsg{
+		Family: syscall.AF_UNSPEC,
+		Flags:  syscall.IFF_UP,
+		Change: syscall.IFF_UP,
+	}
+	data := append(structBytes(unsafe.Pointer(&msg), syscall.SizeofIfInfomsg),
+		rtattr(syscall.IFLA_IFNAME, append([]byte(name), 0))...)
+	data = append(data, rtattr(syscall.IFLA_LINKINFO, rtattr(iflaInfoKind, []byte("dummy")))...)
+	return r.request(syscall.RTM_NEWLINK, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL, data)
+}
+
+func (r *rtnetlink) delLink(index int) error {
+	msg := syscall.IfInfomsg{
+		Family: syscall.AF_UNSPEC,
+		Index:  int32(index),
+	}
+	return r.request(syscall.RTM_DELLINK, 0, structBytes(unsafe.Pointer(&msg), syscall.SizeofIfInfomsg))
+}
+
+func (r *rtnetlink) request(msgType uint16, flags uint16, data []byte) error {
+	r.seq++
+	hdr := syscall.NlMsghdr{
+		Len:   uint32(syscall.NLMSG_HDRLEN + len(data)),
+		Type:  msgType,
+		Flags: syscall.NLM_F_REQUEST | syscall.NLM_F_ACK | flags,
+		Seq:   r.seq,
+	}
+	msg := append(structBytes(unsafe.Pointer(&hdr), syscall.NLMSG_HDRLEN), data...)
+	if err := syscall.Sendto(r.fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, syscall.Getpagesize())
+	for {
+		n, _, err := syscall.Recvfrom(r.fd, buf, 0)
+		if err != nil {
+			return err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != r.seq || m.Header.Type != syscall.NLMSG_ERROR {
+				continue
+			}
+			if len(m.Data) < 4 {
+				return fmt.Errorf("short netlink ack")
+			}
+			if errno := -*(*int32)(unsafe.Pointer(&m.Data[0])); errno != 0 {
+				return syscall.Errno(errno)
+			}
+			return nil
+		}
+	}
+}
+
+func addrMessage(index int, ip net.IP) []byte {
+	msg := syscall.IfAddrmsg{
+		Family:    syscall.AF_INET,
+		Prefixlen: 32,
+		Scope:     syscall.RT_SCOPE_HOST,
+		Index:     uint32(index),
+	}
+	data := structBytes(unsafe.Pointer(&msg), syscall.SizeofIfAddrmsg)
+	data = append(data, rtattr(syscall.IFA_LOCAL, ip.To4())...)
+	return append(data, rtattr(syscall.IFA_ADDRESS, ip.To4())...)
+}
+
+func rtattr(attrType uint16, value []byte) []byte {
+	attr := syscall.RtAttr{
+		Len:  uint16(syscall.SizeofRtAttr + len(value)),
+		Type: attrType,
+	}
+	data := append(structBytes(unsafe.Pointer(&attr), syscall.SizeofRtAttr), value...)
+	for len(data)%nlmsgAlignTo != 0 {
+		data = append(data, 0)
+	}
+	return data
+}
+
+func structBytes(p unsafe.Pointer, size int) []byte {
+	b := make([]byte, size)
+	copy(b, (*[1 << 16]byte)(p)[:size:size])
+	return b
+}
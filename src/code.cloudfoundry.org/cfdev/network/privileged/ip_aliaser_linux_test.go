@@ -0,0 +1,73 @@
+package privileged
+
+import (
+	"net"
+
+	"code.cloudfoundry.org/cfdev/network"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IP Aliaser - Linux", func() {
+	var hostnet *network.HostNet
+
+	addrsOn := func(iface string) []string {
+		i, err := net.InterfaceByName(iface)
+		if err != nil {
+			return nil
+		}
+		addrs, err := i.Addrs()
+		Expect(err).NotTo(HaveOccurred())
+
+		var ips []string
+		for _, addr := range addrs {
+			ips = append(ips, addr.(*net.IPNet).IP.String())
+		}
+		return ips
+	}
+
+	for _, iface := range []string{"", "cfdevtest0"} {
+		iface := iface
+
+		Context("with interface '"+iface+"'", func() {
+			var name string
+
+			BeforeEach(func() {
+				hostnet = &network.HostNet{Interface: iface}
+				name = iface
+				if name == "" {
+					name = "lo"
+				}
+			})
+
+			AfterEach(func() {
+				Expect(hostnet.RemoveLoopbackAliases("10.250.0.10", "10.250.0.11")).To(Succeed())
+			})
+
+			It("adds and removes the aliases idempotently", func() {
+				Expect(hostnet.AddLoopbackAliases("10.250.0.10", "10.250.0.11")).To(Succeed())
+				Expect(hostnet.AddLoopbackAliases("10.250.0.10", "10.250.0.11")).To(Succeed())
+				Expect(addrsOn(name)).To(ContainElement("10.250.0.10"))
+				Expect(addrsOn(name)).To(ContainElement("10.250.0.11"))
+
+				Expect(hostnet.RemoveLoopbackAliases("10.250.0.10", "10.250.0.11")).To(Succeed())
+				Expect(hostnet.RemoveLoopbackAliases("10.250.0.10", "10.250.0.11")).To(Succeed())
+				Expect(addrsOn(name)).NotTo(ContainElement("10.250.0.10"))
+				Expect(addrsOn(name)).NotTo(ContainElement("10.250.0.11"))
+			})
+		})
+	}
+
+	Context("with a dedicated interface", func() {
+		It("deletes the interface once its aliases are removed", func() {
+			hostnet = &network.HostNet{Interface: "cfdevtest0"}
+			Expect(hostnet.AddLoopbackAliases("10.250.0.10")).To(Succeed())
+			_, err := net.InterfaceByName("cfdevtest0")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(hostnet.RemoveLoopbackAliases("10.250.0.10")).To(Succeed())
+			_, err = net.InterfaceByName("cfdevtest0")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,22 +1,25 @@
 package network
 
 import (
-	"code.cloudfoundry.org/cfdev/config"
-	"time"
-	"path/filepath"
-	"code.cloudfoundry.org/cfdev/env"
 	"encoding/json"
-	"code.cloudfoundry.org/cfdev/errors"
-	"os"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cfdev/config"
 	"code.cloudfoundry.org/cfdev/daemon"
+	"code.cloudfoundry.org/cfdev/env"
+	"code.cloudfoundry.org/cfdev/errors"
 )
 
 const VpnKitLabel = "org.cloudfoundry.cfdev.vpnkit"
 
 type VpnKit struct {
-	Config  config.Config
+	Config       config.Config
 	DaemonRunner DaemonRunner
+	SystemDomain string
 }
 
 type DaemonRunner interface {
@@ -28,13 +31,17 @@ type DaemonRunner interface {
 }
 
 func (v *VpnKit) Stop() error {
-	return v.DaemonRunner.Stop(VpnKitLabel)
+	return v.DaemonRunner.Stop(v.label())
+}
+
+func (v *VpnKit) IsRunning() (bool, error) {
+	return v.DaemonRunner.IsRunning(v.label())
 }
 
 func (v *VpnKit) Watch(exit chan string) {
 	go func() {
 		for {
-			running, err := v.DaemonRunner.IsRunning(VpnKitLabel)
+			running, err := v.DaemonRunner.IsRunning(v.label())
 			if !running && err == nil {
 				exit <- "vpnkit"
 				return
@@ -44,11 +51,27 @@ func (v *VpnKit) Watch(exit chan string) {
 	}()
 }
 
-func (v *VpnKit) writeHttpConfig() error{
+func (v *VpnKit) label() string {
+	return v.Config.Label(VpnKitLabel)
+}
+
+// hostNames are the names vpnkit resolves to the host from inside the vm
+func (v *VpnKit) hostNames() string {
+	names := []string{"host.cfdev.sh"}
+	if v.SystemDomain != "" && v.SystemDomain != config.DefaultSystemDomain {
+		names = append(names, "host."+v.SystemDomain)
+	}
+	return strings.Join(names, ",")
+}
+
+func (v *VpnKit) writeHttpConfig() error {
 	httpProxyPath := filepath.Join(v.Config.VpnKitStateDir, "http_proxy.json")
 
-	proxyConfig := env.BuildProxyConfig(v.Config.BoshDirectorIP, v.Config.CFRouterIP, v.Config.HostIP)
-	proxyContents, err := json.Marshal(proxyConfig)
+	proxy, err := env.BuildProxy(v.Config.BoshDirectorIP, v.Config.CFRouterIP, v.Config.HostIP, v.SystemDomain)
+	if err != nil {
+		return err
+	}
+	proxyContents, err := json.Marshal(proxy.Config())
 	if err != nil {
 		return errors.SafeWrap(err, "Unable to create proxy config")
 	}
@@ -61,10 +84,17 @@ func (v *VpnKit) writeHttpConfig() error{
 	}
 
 	httpProxyConfig := []byte(proxyContents)
-	err = ioutil.WriteFile(httpProxyPath, httpProxyConfig, 0777)
+	// the proxy credentials are only readable by the user
+	err = ioutil.WriteFile(httpProxyPath, httpProxyConfig, 0600)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// UpdateProxy rewrites the proxy config after the host proxy changed, vpnkit
+// reloads it without a restart
+func (v *VpnKit) UpdateProxy(systemDomain string) error {
+	v.SystemDomain = systemDomain
+	return v.writeHttpConfig()
+}
@@ -14,14 +14,15 @@ import (
 
 const retries = 5
 
-func (v *VpnKit) Start() error {
+func (v *VpnKit) Start(systemDomain string) error {
+	v.SystemDomain = systemDomain
 	if err := v.Setup(); err != nil {
 		return errors.SafeWrap(err, "Failed to Setup VPNKit")
 	}
 	if err := v.DaemonRunner.AddDaemon(v.daemonSpec()); err != nil {
 		return errors.SafeWrap(err, "install vpnkit")
 	}
-	if err := v.DaemonRunner.Start(VpnKitLabel); err != nil {
+	if err := v.DaemonRunner.Start(v.label()); err != nil {
 		return errors.SafeWrap(err, "start vpnkit")
 	}
 	attempt := 0
@@ -40,12 +41,12 @@ func (v *VpnKit) Start() error {
 }
 
 func (v *VpnKit) Destroy() error {
-	return v.DaemonRunner.RemoveDaemon(VpnKitLabel)
+	return v.DaemonRunner.RemoveDaemon(v.label())
 }
 
 func (v *VpnKit) daemonSpec() daemon.DaemonSpec {
 	return daemon.DaemonSpec{
-		Label:       VpnKitLabel,
+		Label:       v.label(),
 		Program:     path.Join(v.Config.CacheDir, "vpnkit"),
 		SessionType: "Background",
 		ProgramArguments: []string{
@@ -54,11 +55,12 @@ func (v *VpnKit) daemonSpec() daemon.DaemonSpec {
 			"--port", path.Join(v.Config.VpnKitStateDir, "vpnkit_port.sock"),
 			"--vsock-path", path.Join(v.Config.StateDir, "connect"),
 			"--http", path.Join(v.Config.VpnKitStateDir, "http_proxy.json"),
-			"--host-names", "host.cfdev.sh",
+			"--host-names", v.hostNames(),
+			"--host-ip", v.Config.HostIP,
 		},
 		RunAtLoad:  false,
-		StdoutPath: path.Join(v.Config.CFDevHome, "vpnkit.stdout.log"),
-		StderrPath: path.Join(v.Config.CFDevHome, "vpnkit.stderr.log"),
+		StdoutPath: path.Join(v.Config.EnvDir, "vpnkit.stdout.log"),
+		StderrPath: path.Join(v.Config.EnvDir, "vpnkit.stderr.log"),
 	}
 }
 
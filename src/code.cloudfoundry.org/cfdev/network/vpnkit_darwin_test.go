@@ -57,7 +57,7 @@ var _ = Describe("VpnKit", func() {
 	})
 
 	It("starts vpnkit", func() {
-		Expect(vkit.Start()).To(Succeed())
+		Expect(vkit.Start("dev.cfdev.sh")).To(Succeed())
 		conn, err := net.Dial("unix", filepath.Join(vpnkitStateDir, "vpnkit_eth.sock"))
 		defer conn.Close()
 		Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,8 @@
+package network
+
+// On linux the vm is on the qemu user network, so vpnkit is not started and
+// apps get the host proxy through the env var groups. Destroy removes the
+// daemon earlier versions installed.
+func (v *VpnKit) Destroy() error {
+	return v.DaemonRunner.RemoveDaemon(v.label())
+}
@@ -20,12 +20,13 @@ const ethernetGUID = "7207f451-2ca3-4b88-8d01-820a21d78293"
 const portGUID = "cc2a519a-fb40-4e45-a9f1-c7f04c5ad7fa"
 const forwarderGUID = "e3ae8f06-8c25-47fb-b6ed-c20702bcef5e"
 
-func (v *VpnKit) Start() error {
+func (v *VpnKit) Start(systemDomain string) error {
+	v.SystemDomain = systemDomain
 	if err := v.Setup(); err != nil {
 		return errors.SafeWrap(err, "Failed to Setup VPNKit")
 	}
 
-	cmd := exec.Command("powershell.exe", "-Command", "((Get-VM -Name cfdev).Id).Guid")
+	cmd := exec.Command("powershell.exe", "-Command", fmt.Sprintf("((Get-VM -Name %s).Id).Guid", v.Config.VMName()))
 	output, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("get vm name: %s", err)
@@ -38,7 +39,7 @@ func (v *VpnKit) Start() error {
 		return errors.SafeWrap(err, "install vpnkit")
 	}
 
-	if err := v.DaemonRunner.Start(VpnKitLabel); err != nil {
+	if err := v.DaemonRunner.Start(v.label()); err != nil {
 		return errors.SafeWrap(err, "start vpnkit")
 	}
 
@@ -46,7 +47,7 @@ func (v *VpnKit) Start() error {
 }
 
 func (v *VpnKit) Destroy() error {
-	v.DaemonRunner.RemoveDaemon(VpnKitLabel)
+	v.DaemonRunner.RemoveDaemon(v.label())
 	registryDeleteCmd := `Get-ChildItem "HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Virtualization\GuestCommunicationServices" | ` +
 		`Where-Object { $_.GetValue("ElementName") -match "CF Dev VPNKit" } | ` +
 		`Foreach-Object { Remove-Item (Join-Path "HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Virtualization\GuestCommunicationServices" $_.PSChildName) }`
@@ -57,11 +58,11 @@ func (v *VpnKit) Destroy() error {
 }
 
 func (v *VpnKit) daemonSpec(vmGuid string) daemon.DaemonSpec {
-	dnsPath := filepath.Join(v.Config.CFDevHome, "resolv.conf")
-	dhcpPath := filepath.Join(v.Config.CFDevHome, "dhcp.json")
+	dnsPath := filepath.Join(v.Config.EnvDir, "resolv.conf")
+	dhcpPath := filepath.Join(v.Config.EnvDir, "dhcp.json")
 
 	return daemon.DaemonSpec{
-		Label:   VpnKitLabel,
+		Label:   v.label(),
 		Program: path.Join(v.Config.CacheDir, "vpnkit.exe"),
 		ProgramArguments: []string{
 			fmt.Sprintf("--ethernet hyperv-connect://%s/%s", vmGuid, ethernetGUID),
@@ -70,11 +71,12 @@ func (v *VpnKit) daemonSpec(vmGuid string) daemon.DaemonSpec {
 			fmt.Sprintf("--dns %s", dnsPath),
 			fmt.Sprintf("--dhcp %s", dhcpPath),
 			"--http", path.Join(v.Config.VpnKitStateDir, "http_proxy.json"),
-			"--host-names host.cfdev.sh",
+			"--host-names " + v.hostNames(),
+			fmt.Sprintf("--host-ip %s", v.Config.HostIP),
 		},
 		RunAtLoad:  false,
-		StdoutPath: path.Join(v.Config.CFDevHome, "vpnkit.stdout.log"),
-		StderrPath: path.Join(v.Config.CFDevHome, "vpnkit.stderr.log"),
+		StdoutPath: path.Join(v.Config.EnvDir, "vpnkit.stdout.log"),
+		StderrPath: path.Join(v.Config.EnvDir, "vpnkit.stderr.log"),
 	}
 }
 
@@ -136,7 +138,7 @@ func (v *VpnKit) writeResolvConf() error{
 		dnsFile += fmt.Sprintf("nameserver %s\r\n", line)
 	}
 
-	resolvConfPath := filepath.Join(v.Config.CFDevHome, "resolv.conf")
+	resolvConfPath := filepath.Join(v.Config.EnvDir, "resolv.conf")
 	if fileExists(resolvConfPath) {
 		os.RemoveAll(resolvConfPath)
 	}
@@ -169,7 +171,7 @@ func (v *VpnKit) writeDHCPJSON() error{
 		}
 	}
 
-	dhcpJsonPath := filepath.Join(v.Config.CFDevHome, "dhcp.json")
+	dhcpJsonPath := filepath.Join(v.Config.EnvDir, "dhcp.json")
 	if fileExists(dhcpJsonPath) {
 		os.RemoveAll(dhcpJsonPath)
 	}
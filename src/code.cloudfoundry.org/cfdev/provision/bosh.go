@@ -3,6 +3,7 @@ package provision
 import (
 	"fmt"
 
+	"code.cloudfoundry.org/cfdev/config"
 	"code.cloudfoundry.org/cfdev/errors"
 	"code.cloudfoundry.org/garden"
 )
@@ -11,7 +12,7 @@ func (c *Controller) DeployBosh() error {
 	containerSpec := garden.ContainerSpec{
 		Handle:     "deploy-bosh",
 		Privileged: true,
-		Network:    "10.246.0.0/16",
+		Network:    c.containerNetwork(),
 		Image: garden.ImageRef{
 			URI: "/var/vcap/cache/workspace.tar",
 		},
@@ -29,7 +30,21 @@ func (c *Controller) DeployBosh() error {
 		},
 	}
 
-	container, err := c.Client.Create(containerSpec)
+	networkEnv, err := c.networkEnv()
+	if err != nil {
+		return err
+	}
+	containerSpec.Env = append(containerSpec.Env, "BOSH_DIRECTOR_IP="+c.boshDirectorIP())
+	containerSpec.Env = append(containerSpec.Env, "DNS_IP="+config.DefaultDNSIP)
+	containerSpec.Env = append(containerSpec.Env, networkEnv...)
+
+	container, err := c.createContainer(containerSpec)
+	if err != nil {
+		return err
+	}
+	defer c.Client.Destroy("deploy-bosh")
+
+	env, err := c.streamOverrides(container, BoshDeployment)
 	if err != nil {
 		return err
 	}
@@ -39,6 +54,7 @@ func (c *Controller) DeployBosh() error {
 		Path: "/bin/bash",
 		Args: []string{"/var/vcap/cache/bin/deploy-bosh"},
 		User: "root",
+		Env:  env,
 	}, garden.ProcessIO{})
 
 	if err != nil {
@@ -54,7 +70,5 @@ func (c *Controller) DeployBosh() error {
 		return errors.SafeWrap(nil, fmt.Sprintf("process exited with status %v", exitCode))
 	}
 
-	c.Client.Destroy("deploy-bosh")
-
 	return nil
 }
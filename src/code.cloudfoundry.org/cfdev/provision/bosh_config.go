@@ -16,7 +16,7 @@ func (c *Controller) FetchBOSHConfig() (bosh.Config, error) {
 	containerSpec := garden.ContainerSpec{
 		Handle:     "fetch-bosh-config",
 		Privileged: true,
-		Network:    "10.246.0.0/16",
+		Network:    c.containerNetwork(),
 		Image: garden.ImageRef{
 			URI: "/var/vcap/cache/workspace.tar",
 		},
@@ -44,7 +44,7 @@ func (c *Controller) FetchBOSHConfig() (bosh.Config, error) {
 		return bosh.Config{}, err
 	}
 
-	return resp.convert()
+	return resp.convert(c.boshDirectorIP())
 }
 
 func (c *Controller) fetchBOSHConfig(container garden.Container, resp *yamlResponse) error {
@@ -88,7 +88,7 @@ type yamlResponse struct {
 	} `yaml:"jumpbox_ssh"`
 }
 
-func (r *yamlResponse) convert() (bosh.Config, error) {
+func (r *yamlResponse) convert(directorAddress string) (bosh.Config, error) {
 	conf := bosh.Config{}
 
 	if r.AdminPassword == "" {
@@ -103,7 +103,7 @@ func (r *yamlResponse) convert() (bosh.Config, error) {
 		return conf, errors.SafeWrap(nil, "jumpbox ssh key was not returned")
 	}
 
-	conf.DirectorAddress = "10.245.0.2"
+	conf.DirectorAddress = directorAddress
 	conf.AdminUsername = "admin"
 	conf.AdminPassword = r.AdminPassword
 	conf.CACertificate = r.DirectorSSL.CACertificate
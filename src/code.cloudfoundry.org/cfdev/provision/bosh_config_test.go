@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"code.cloudfoundry.org/cfdev/bosh"
+	"code.cloudfoundry.org/cfdev/config"
 	"code.cloudfoundry.org/cfdev/provision"
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/gardenfakes"
@@ -103,6 +104,22 @@ var _ = Describe("Fetching BOSH Configuration", func() {
 			})
 		})
 
+		Context("when the network addresses are configured", func() {
+			BeforeEach(func() {
+				gclient.Config = config.Config{
+					BoshDirectorIP:   "172.20.0.2",
+					ContainerNetwork: "172.22.0.0/16",
+				}
+				fakeContainer.RunStub = successfulRunStub
+			})
+
+			It("uses them for the container and the director address", func() {
+				Expect(fakeClient.CreateArgsForCall(0).Network).To(Equal("172.22.0.0/16"))
+				Expect(boshConfig.DirectorAddress).To(Equal("172.20.0.2"))
+				Expect(boshConfig.GatewayHost).To(Equal("172.20.0.2"))
+			})
+		})
+
 		Context("when fetching does not start", func() {
 			BeforeEach(func() {
 				fakeContainer.RunReturns(nil, errors.New("unable to start process"))
@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"code.cloudfoundry.org/cfdev/config"
 	"code.cloudfoundry.org/cfdev/provision"
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/gardenfakes"
@@ -32,6 +33,8 @@ var _ = Describe("DeployBosh", func() {
 		Expect(fakeClient.CreateCallCount()).To(Equal(1))
 		spec := fakeClient.CreateArgsForCall(0)
 
+		Expect(spec.Env).To(ConsistOf("BOSH_DIRECTOR_IP=10.245.0.2", "DNS_IP="+config.DefaultDNSIP, HavePrefix("NETWORK_VARS=")))
+		spec.Env = nil
 		Expect(spec).To(Equal(garden.ContainerSpec{
 			Handle:     "deploy-bosh",
 			Privileged: true,
@@ -89,9 +92,8 @@ var _ = Describe("DeployBosh", func() {
 			})
 
 			It("deletes the container", func() {
-				Expect(fakeClient.DestroyCallCount()).To(Equal(1))
-				handle := fakeClient.DestroyArgsForCall(0)
-				Expect(handle).To(Equal("deploy-bosh"))
+				Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-bosh"))
 			})
 		})
 
@@ -115,6 +117,21 @@ var _ = Describe("DeployBosh", func() {
 			It("returns an error", func() {
 				Expect(err).To(MatchError("process exited with status 23"))
 			})
+
+			It("deletes the container", func() {
+				Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-bosh"))
+			})
+
+			It("can be retried", func() {
+				process := new(gardenfakes.FakeProcess)
+				process.WaitReturns(0, nil)
+				fakeContainer.RunReturns(process, nil)
+
+				Expect(gclient.DeployBosh()).To(Succeed())
+				Expect(fakeClient.CreateCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyCallCount()).To(Equal(4))
+			})
 		})
 
 		Context("when we cannot determine the state of the deploy", func() {
@@ -138,5 +155,10 @@ var _ = Describe("DeployBosh", func() {
 		It("forwards the error", func() {
 			Expect(err).To(MatchError("unable to create container"))
 		})
+
+		It("first removes a container left behind by an earlier run", func() {
+			Expect(fakeClient.DestroyCallCount()).To(Equal(1))
+			Expect(fakeClient.DestroyArgsForCall(0)).To(Equal("deploy-bosh"))
+		})
 	})
 })
@@ -0,0 +1,80 @@
+package provision
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const trustedCertsBoshOps = `- type: replace
+  path: /instance_groups/name=bosh/properties/director/trusted_certs?
+  value: ((trusted_ca_certs))
+`
+
+const trustedCertsCFOps = `- type: replace
+  path: /instance_groups/name=diego-cell/jobs/name=rep/properties/diego?/trusted_certs?
+  value: ((trusted_ca_certs))
+
+- type: replace
+  path: /instance_groups/name=diego-cell/jobs/name=cflinuxfs2-rootfs-setup/properties/cflinuxfs2-rootfs?/trusted_certs?
+  value: ((trusted_ca_certs))
+`
+
+// AddTrustedCerts makes the deploy containers, the director and the vms it
+// creates, and the app containers on the cells trust the PEM encoded CA
+// certs.
+func (o *Overrides) AddTrustedCerts(certs ...string) {
+	if len(certs) == 0 {
+		return
+	}
+	if len(o.TrustedCerts) == 0 {
+		o.Ops = append(o.Ops,
+			Ops{Deployment: BoshDeployment, Name: "trusted-certs.yml", Contents: []byte(trustedCertsBoshOps)},
+			Ops{Deployment: CFDeployment, Name: "trusted-certs.yml", Contents: []byte(trustedCertsCFOps)},
+		)
+	}
+
+	for _, cert := range certs {
+		if !contains(o.TrustedCerts, cert) {
+			o.TrustedCerts = append(o.TrustedCerts, cert)
+		}
+	}
+}
+
+// ReadCACerts returns the certificates in the PEM file
+func ReadCACerts(path string) ([]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("CA cert not found: %s", path)
+	}
+	certs := SplitCerts(contents)
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("CA cert is not PEM encoded: %s", path)
+	}
+	return certs, nil
+}
+
+// SplitCerts returns every certificate of the PEM data on its own
+func SplitCerts(data []byte) []string {
+	var certs []string
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if block.Type == "CERTIFICATE" {
+			certs = append(certs, strings.TrimSpace(string(pem.EncodeToMemory(block))))
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -9,11 +9,11 @@ import (
 	"code.cloudfoundry.org/garden"
 )
 
-func (c *Controller) DeployCloudFoundry(dockerRegistries []string) error {
+func (c *Controller) DeployCloudFoundry(dockerRegistries []string, systemDomain string) error {
 	containerSpec := garden.ContainerSpec{
 		Handle:     "deploy-cf",
 		Privileged: true,
-		Network:    "10.246.0.0/16",
+		Network:    c.containerNetwork(),
 		Image: garden.ImageRef{
 			URI: "/var/vcap/cache/workspace.tar",
 		},
@@ -41,7 +41,31 @@ func (c *Controller) DeployCloudFoundry(dockerRegistries []string) error {
 		containerSpec.Env = append(containerSpec.Env, "DOCKER_REGISTRIES="+string(bytes))
 	}
 
-	container, err := c.Client.Create(containerSpec)
+	if c.Config.HostIP != "" {
+		containerSpec.Env = append(containerSpec.Env, "HOST_IP="+c.Config.HostIP)
+	}
+
+	if c.Config.CFRouterIP != "" {
+		containerSpec.Env = append(containerSpec.Env, "ROUTER_IP="+c.Config.CFRouterIP)
+	}
+
+	if systemDomain != "" {
+		containerSpec.Env = append(containerSpec.Env, "CF_DOMAIN="+systemDomain)
+	}
+
+	networkEnv, err := c.networkEnv()
+	if err != nil {
+		return err
+	}
+	containerSpec.Env = append(containerSpec.Env, networkEnv...)
+
+	container, err := c.createContainer(containerSpec)
+	if err != nil {
+		return err
+	}
+	defer c.Client.Destroy("deploy-cf")
+
+	env, err := c.streamOverrides(container, CFDeployment)
 	if err != nil {
 		return err
 	}
@@ -51,6 +75,7 @@ func (c *Controller) DeployCloudFoundry(dockerRegistries []string) error {
 		Path: "/bin/bash",
 		Args: []string{"/var/vcap/cache/bin/deploy-cf"},
 		User: "root",
+		Env:  env,
 	}, garden.ProcessIO{})
 
 	if err != nil {
@@ -66,7 +91,5 @@ func (c *Controller) DeployCloudFoundry(dockerRegistries []string) error {
 		return errors.SafeWrap(nil, fmt.Sprintf("process exited with status %d", exitCode))
 	}
 
-	c.Client.Destroy("deploy-cf")
-
 	return nil
 }
@@ -18,6 +18,7 @@ var _ = Describe("DeployCloudFoundry", func() {
 		fakeClient       *gardenfakes.FakeClient
 		err              error
 		dockerRegistries []string
+		systemDomain     string
 		gclient          *provision.Controller
 	)
 
@@ -25,16 +26,19 @@ var _ = Describe("DeployCloudFoundry", func() {
 		fakeClient = new(gardenfakes.FakeClient)
 		fakeClient.CreateReturns(nil, errors.New("some error"))
 		gclient = &provision.Controller{Client: fakeClient}
+		systemDomain = ""
 	})
 
 	JustBeforeEach(func() {
-		err = gclient.DeployCloudFoundry(dockerRegistries)
+		err = gclient.DeployCloudFoundry(dockerRegistries, systemDomain)
 	})
 
 	It("creates a container", func() {
 		Expect(fakeClient.CreateCallCount()).To(Equal(1))
 		spec := fakeClient.CreateArgsForCall(0)
 
+		Expect(spec.Env).To(ConsistOf(HavePrefix("NETWORK_VARS=")))
+		spec.Env = nil
 		Expect(spec).To(Equal(garden.ContainerSpec{
 			Handle:     "deploy-cf",
 			Privileged: true,
@@ -83,6 +87,17 @@ var _ = Describe("DeployCloudFoundry", func() {
 		})
 	})
 
+	Context("when a system domain is provided", func() {
+		BeforeEach(func() {
+			systemDomain = "cf.local"
+		})
+
+		It("sets the CF_DOMAIN variable when creating the container", func() {
+			spec := fakeClient.CreateArgsForCall(0)
+			Expect(spec.Env).To(ContainElement("CF_DOMAIN=cf.local"))
+		})
+	})
+
 	Context("creating the container succeeds", func() {
 		var (
 			fakeContainer *gardenfakes.FakeContainer
@@ -119,9 +134,8 @@ var _ = Describe("DeployCloudFoundry", func() {
 			})
 
 			It("deletes the container", func() {
-				Expect(fakeClient.DestroyCallCount()).To(Equal(1))
-				handle := fakeClient.DestroyArgsForCall(0)
-				Expect(handle).To(Equal("deploy-cf"))
+				Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-cf"))
 			})
 		})
 
@@ -145,6 +159,21 @@ var _ = Describe("DeployCloudFoundry", func() {
 			It("returns an error", func() {
 				Expect(err).To(MatchError("process exited with status 23"))
 			})
+
+			It("deletes the container", func() {
+				Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-cf"))
+			})
+
+			It("can be retried", func() {
+				process := new(gardenfakes.FakeProcess)
+				process.WaitReturns(0, nil)
+				fakeContainer.RunReturns(process, nil)
+
+				Expect(gclient.DeployCloudFoundry(dockerRegistries, systemDomain)).To(Succeed())
+				Expect(fakeClient.CreateCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyCallCount()).To(Equal(4))
+			})
 		})
 
 		Context("when we cannot determine the state of the deploy", func() {
@@ -168,5 +197,10 @@ var _ = Describe("DeployCloudFoundry", func() {
 		It("forwards the error", func() {
 			Expect(err).To(MatchError("unable to create container"))
 		})
+
+		It("first removes a container left behind by an earlier run", func() {
+			Expect(fakeClient.DestroyCallCount()).To(Equal(1))
+			Expect(fakeClient.DestroyArgsForCall(0)).To(Equal("deploy-cf"))
+		})
 	})
 })
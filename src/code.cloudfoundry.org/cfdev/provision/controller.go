@@ -1,20 +1,52 @@
 package provision
 
 import (
-	garden "code.cloudfoundry.org/garden/client"
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/garden"
+	gardenclient "code.cloudfoundry.org/garden/client"
 	"code.cloudfoundry.org/garden/client/connection"
 )
 
 type Controller struct {
 	Client garden.Client
+	Config config.Config
 }
 
-func NewController() *Controller {
+func NewController(config config.Config) *Controller {
 	return &Controller{
-		Client: garden.New(connection.New("tcp", "localhost:8888")),
+		Client: gardenclient.New(connection.New("tcp", config.GardenAddr())),
+		Config: config,
 	}
 }
 
+func (c *Controller) containerNetwork() string {
+	if c.Config.ContainerNetwork == "" {
+		return config.DefaultContainerNetwork
+	}
+	return c.Config.ContainerNetwork
+}
+
+func (c *Controller) boshDirectorIP() string {
+	if c.Config.BoshDirectorIP == "" {
+		return config.DefaultBoshDirectorIP
+	}
+	return c.Config.BoshDirectorIP
+}
+
+func (c *Controller) routerIP() string {
+	if c.Config.CFRouterIP == "" {
+		return config.DefaultCFRouterIP
+	}
+	return c.Config.CFRouterIP
+}
+
+// createContainer replaces a container that a failed or interrupted
+// deploy left behind under the same handle
+func (c *Controller) createContainer(spec garden.ContainerSpec) (garden.Container, error) {
+	c.Client.Destroy(spec.Handle)
+	return c.Client.Create(spec)
+}
+
 func (c *Controller) Ping() error {
 	return c.Client.Ping()
 }
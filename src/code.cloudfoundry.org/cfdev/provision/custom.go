@@ -0,0 +1,207 @@
+package provision
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/garden"
+)
+
+const (
+	customDeploymentsFile = "deployments.json"
+	containerDeployDir    = "/tmp/cfdev-deploy"
+)
+
+const deployManifestScript = `set -e
+export LOG_DIR=/var/vcap/logs
+mkdir -p "${LOG_DIR}"
+exec 1> >(tee -i "${LOG_DIR}/deploy-${DEPLOYMENT_NAME}.log")
+exec 2>&1
+
+if [ -d "${OVERRIDES_DIR}/certs" ]; then
+  mkdir -p /usr/local/share/ca-certificates
+  cp "${OVERRIDES_DIR}"/certs/*.crt /usr/local/share/ca-certificates/
+  update-ca-certificates
+fi
+
+source /var/vcap/director/env
+
+for release in "${DEPLOY_DIR}"/releases/*; do
+  if [ -f "$release" ]; then bosh --tty upload-release "$release"; fi
+done
+
+override_args=()
+if [ -d "${OVERRIDES_DIR}" ]; then
+  for f in "${OVERRIDES_DIR}"/ops/*; do
+    if [ -f "$f" ]; then override_args+=(-o "$f"); fi
+  done
+  for f in "${OVERRIDES_DIR}"/vars/*; do
+    if [ -f "$f" ]; then override_args+=(-l "$f"); fi
+  done
+fi
+
+bosh --tty --non-interactive --deployment "${DEPLOYMENT_NAME}" \
+  deploy "${DEPLOY_DIR}/manifest.yml" \
+  "${override_args[@]}" \
+  --no-redact
+`
+
+// ManifestName returns the name of the deployment a BOSH manifest describes
+func ManifestName(manifestPath string) (string, error) {
+	contents, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return "", errors.SafeWrap(err, "failed to read manifest")
+	}
+
+	var manifest struct {
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal(contents, &manifest); err != nil {
+		return "", errors.SafeWrap(err, "failed to parse manifest")
+	}
+	if manifest.Name == "" {
+		return "", fmt.Errorf("manifest %s does not name its deployment", manifestPath)
+	}
+	return manifest.Name, nil
+}
+
+// DeployManifest streams the manifest and the releases into a container,
+// uploads the releases to the director and deploys the manifest as the named
+// deployment.
+func (c *Controller) DeployManifest(name, manifestPath string, releases []string) error {
+	handle := "deploy-" + name
+
+	container, err := c.createContainer(c.containerSpec(handle))
+	if err != nil {
+		return err
+	}
+	defer c.Client.Destroy(handle)
+
+	tarStream := deployTar(manifestPath, releases)
+	defer tarStream.Close()
+	if err := container.StreamIn(garden.StreamInSpec{
+		Path:      containerDeployDir,
+		User:      "root",
+		TarStream: tarStream,
+	}); err != nil {
+		return errors.SafeWrap(err, "failed to copy the manifest and releases")
+	}
+
+	env, err := c.streamOverrides(container, name)
+	if err != nil {
+		return err
+	}
+
+	process, err := container.Run(garden.ProcessSpec{
+		ID:   handle,
+		Path: "/bin/bash",
+		Args: []string{"-c", deployManifestScript},
+		User: "root",
+		Env:  append(env, "DEPLOYMENT_NAME="+name, "DEPLOY_DIR="+containerDeployDir),
+	}, garden.ProcessIO{})
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := process.Wait()
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return errors.SafeWrap(nil, fmt.Sprintf("process exited with status %d", exitCode))
+	}
+
+	return nil
+}
+
+// deployTar streams the manifest and the releases without reading the
+// releases into memory
+func deployTar(manifestPath string, releases []string) *io.PipeReader {
+	r, w := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(w)
+		err := addFile(tw, manifestPath, "manifest.yml")
+		for _, release := range releases {
+			if err != nil {
+				break
+			}
+			err = addFile(tw, release, "releases/"+filepath.Base(release))
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		w.CloseWithError(err)
+	}()
+	return r
+}
+
+func addFile(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: info.Size()}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// CustomDeployments returns the names of the deployments added with cf dev
+// deploy
+func CustomDeployments(stateDir string) []string {
+	contents, err := ioutil.ReadFile(filepath.Join(stateDir, customDeploymentsFile))
+	if err != nil {
+		return []string{}
+	}
+
+	var deployments struct {
+		Deployments []string `json:"deployments"`
+	}
+	if err := json.Unmarshal(contents, &deployments); err != nil {
+		return []string{}
+	}
+	return deployments.Deployments
+}
+
+// RecordCustomDeployment adds the deployment to or removes it from the
+// deployments added with cf dev deploy
+func RecordCustomDeployment(stateDir, name string, deployed bool) error {
+	names := []string{}
+	for _, n := range CustomDeployments(stateDir) {
+		if n != name {
+			names = append(names, n)
+		}
+	}
+	if deployed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	contents, err := json.Marshal(struct {
+		Deployments []string `json:"deployments"`
+	}{names})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(stateDir, customDeploymentsFile), contents, 0644)
+}
@@ -0,0 +1,146 @@
+package provision_test
+
+import (
+	"archive/tar"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cfdev/provision"
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+)
+
+var _ = Describe("custom deployments", func() {
+	var (
+		tmpDir   string
+		manifest string
+		release  string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "custom-deployments")
+		Expect(err).NotTo(HaveOccurred())
+		manifest = filepath.Join(tmpDir, "manifest.yml")
+		Expect(ioutil.WriteFile(manifest, []byte("name: my-deployment\n"), 0644)).To(Succeed())
+		release = filepath.Join(tmpDir, "my-release.tgz")
+		Expect(ioutil.WriteFile(release, []byte("release"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("ManifestName", func() {
+		It("reads the deployment name", func() {
+			Expect(provision.ManifestName(manifest)).To(Equal("my-deployment"))
+		})
+
+		It("rejects manifests without a name", func() {
+			Expect(ioutil.WriteFile(manifest, []byte("releases: []\n"), 0644)).To(Succeed())
+			_, err := provision.ManifestName(manifest)
+			Expect(err).To(MatchError(ContainSubstring("does not name its deployment")))
+		})
+	})
+
+	Describe("RecordCustomDeployment", func() {
+		It("adds and removes deployments", func() {
+			Expect(provision.CustomDeployments(tmpDir)).To(BeEmpty())
+			Expect(provision.RecordCustomDeployment(tmpDir, "b", true)).To(Succeed())
+			Expect(provision.RecordCustomDeployment(tmpDir, "a", true)).To(Succeed())
+			Expect(provision.RecordCustomDeployment(tmpDir, "a", true)).To(Succeed())
+			Expect(provision.CustomDeployments(tmpDir)).To(Equal([]string{"a", "b"}))
+
+			Expect(provision.RecordCustomDeployment(tmpDir, "b", false)).To(Succeed())
+			Expect(provision.CustomDeployments(tmpDir)).To(Equal([]string{"a"}))
+		})
+	})
+
+	Describe("DeployManifest", func() {
+		var (
+			fakeClient    *gardenfakes.FakeClient
+			fakeContainer *gardenfakes.FakeContainer
+			streamed      []string
+			err           error
+		)
+
+		BeforeEach(func() {
+			streamed = nil
+			fakeContainer = new(gardenfakes.FakeContainer)
+			fakeContainer.StreamInStub = func(spec garden.StreamInSpec) error {
+				tr := tar.NewReader(spec.TarStream)
+				for {
+					hdr, err := tr.Next()
+					if err != nil {
+						break
+					}
+					streamed = append(streamed, hdr.Name)
+				}
+				return nil
+			}
+			process := new(gardenfakes.FakeProcess)
+			process.WaitReturns(0, nil)
+			fakeContainer.RunReturns(process, nil)
+			fakeClient = new(gardenfakes.FakeClient)
+			fakeClient.CreateReturns(fakeContainer, nil)
+		})
+
+		JustBeforeEach(func() {
+			controller := &provision.Controller{Client: fakeClient}
+			err = controller.DeployManifest("my-deployment", manifest, []string{release})
+		})
+
+		It("streams the manifest and the releases into the container", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.CreateArgsForCall(0).Handle).To(Equal("deploy-my-deployment"))
+			Expect(fakeContainer.StreamInArgsForCall(0).Path).To(Equal("/tmp/cfdev-deploy"))
+			Expect(streamed).To(Equal([]string{"manifest.yml", "releases/my-release.tgz"}))
+		})
+
+		It("deploys the manifest as the named deployment", func() {
+			spec, _ := fakeContainer.RunArgsForCall(0)
+			Expect(spec.ID).To(Equal("deploy-my-deployment"))
+			Expect(spec.Env).To(ConsistOf("DEPLOYMENT_NAME=my-deployment", "DEPLOY_DIR=/tmp/cfdev-deploy"))
+			Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+			Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-my-deployment"))
+		})
+
+		Context("when the deploy finishes with a non-zero exit code", func() {
+			BeforeEach(func() {
+				process := new(gardenfakes.FakeProcess)
+				process.WaitReturns(1, nil)
+				fakeContainer.RunReturns(process, nil)
+			})
+
+			It("deletes the container so the deploy can be retried", func() {
+				Expect(err).To(MatchError("process exited with status 1"))
+				Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-my-deployment"))
+
+				process := new(gardenfakes.FakeProcess)
+				process.WaitReturns(0, nil)
+				fakeContainer.RunReturns(process, nil)
+				controller := &provision.Controller{Client: fakeClient}
+				Expect(controller.DeployManifest("my-deployment", manifest, []string{release})).To(Succeed())
+				Expect(fakeClient.CreateCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when streaming fails", func() {
+			BeforeEach(func() {
+				fakeContainer.StreamInStub = nil
+				fakeContainer.StreamInReturns(errors.New("some-error"))
+			})
+
+			It("returns the error without deploying", func() {
+				Expect(err).To(MatchError(ContainSubstring("failed to copy the manifest and releases")))
+				Expect(fakeContainer.RunCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
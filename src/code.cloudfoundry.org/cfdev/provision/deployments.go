@@ -0,0 +1,35 @@
+package provision
+
+import "code.cloudfoundry.org/cfdev/bosh"
+
+func (c *Controller) Deployments() ([]bosh.DeploymentStatus, error) {
+	config, err := c.FetchBOSHConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := bosh.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Deployments()
+}
+
+func (c *Controller) DeleteService(service Service) error {
+	return c.DeleteDeployment(service.Deployment)
+}
+
+func (c *Controller) DeleteDeployment(name string) error {
+	config, err := c.FetchBOSHConfig()
+	if err != nil {
+		return err
+	}
+
+	b, err := bosh.New(config)
+	if err != nil {
+		return err
+	}
+
+	return b.DeleteDeployment(name)
+}
@@ -17,7 +17,7 @@ func (c *Controller) FetchLogs(destinationDir string) error {
 	containerSpec := garden.ContainerSpec{
 		Handle:     logsContainerHandle,
 		Privileged: true,
-		Network:    "10.246.0.0/16",
+		Network:    c.containerNetwork(),
 		Image: garden.ImageRef{
 			URI: "/var/vcap/cache/workspace.tar",
 		},
@@ -0,0 +1,30 @@
+package provision
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/cfdev/config"
+)
+
+// networkEnv passes the addresses the director and the cloud config are
+// rendered with to the deploy scripts, which write NETWORK_VARS to a vars
+// file. JSON is valid YAML.
+func (c *Controller) networkEnv() ([]string, error) {
+	network, err := config.BuildNetwork(c.boshDirectorIP(), c.routerIP(), c.containerNetwork())
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := json.Marshal(map[string]interface{}{
+		"internal_ip":       network.DirectorIP,
+		"internal_cidr":     network.DirectorCIDR,
+		"internal_gw":       network.DirectorGateway,
+		"cf_subnet_range":   network.CFSubnet,
+		"cf_subnet_gateway": network.CFGateway,
+		"cf_subnet_static":  network.CFStatic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{"NETWORK_VARS=" + string(vars)}, nil
+}
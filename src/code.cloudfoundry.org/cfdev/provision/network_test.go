@@ -0,0 +1,102 @@
+package provision_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	yaml "gopkg.in/yaml.v2"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/provision"
+	"code.cloudfoundry.org/garden/gardenfakes"
+)
+
+var _ = Describe("network vars", func() {
+	var (
+		fakeClient *gardenfakes.FakeClient
+		controller *provision.Controller
+	)
+
+	BeforeEach(func() {
+		fakeClient = new(gardenfakes.FakeClient)
+		fakeClient.CreateReturns(nil, errors.New("some error"))
+		controller = &provision.Controller{Client: fakeClient, Config: config.Config{
+			BoshDirectorIP:   "172.20.0.2",
+			CFRouterIP:       "192.168.50.34",
+			ContainerNetwork: "172.21.0.0/16",
+		}}
+	})
+
+	networkVars := func() map[string]interface{} {
+		for _, e := range fakeClient.CreateArgsForCall(0).Env {
+			if strings.HasPrefix(e, "NETWORK_VARS=") {
+				var vars map[string]interface{}
+				Expect(yaml.Unmarshal([]byte(strings.TrimPrefix(e, "NETWORK_VARS=")), &vars)).To(Succeed())
+				return vars
+			}
+		}
+		Fail("NETWORK_VARS is not set")
+		return nil
+	}
+
+	// render interpolates an ops file of the cf image the way bosh does for
+	// whole values
+	render := func(path string, vars map[string]interface{}) interface{} {
+		contents, err := ioutil.ReadFile(filepath.Join("..", "..", "..", "..", "images", "cf", path))
+		Expect(err).NotTo(HaveOccurred())
+
+		rendered := regexp.MustCompile(`\(\(([a-z_]+)\)\)`).ReplaceAllStringFunc(string(contents), func(ref string) string {
+			value, ok := vars[strings.Trim(ref, "()")]
+			Expect(ok).To(BeTrue(), "missing var "+ref)
+			out, err := json.Marshal(value)
+			Expect(err).NotTo(HaveOccurred())
+			return string(out)
+		})
+
+		var ops []struct {
+			Value interface{} `yaml:"value"`
+		}
+		Expect(yaml.Unmarshal([]byte(rendered), &ops)).To(Succeed())
+		return ops[0].Value
+	}
+
+	It("renders the cf cloud config subnet for the router address", func() {
+		controller.DeployCloudFoundry(nil, "")
+
+		value := render(filepath.Join("cf-operations", "set-cloud-config-subnet.yml"), networkVars())
+		subnet := value.(map[interface{}]interface{})["subnets"].([]interface{})[0].(map[interface{}]interface{})
+		Expect(subnet["range"]).To(Equal("192.168.0.0/16"))
+		Expect(subnet["gateway"]).To(Equal("192.168.0.1"))
+		Expect(subnet["reserved"]).To(Equal([]interface{}{"192.168.0.1"}))
+		Expect(subnet["static"]).To(Equal([]interface{}{
+			"192.168.50.0 - 192.168.50.127",
+			"192.168.51.0 - 192.168.51.127",
+			"192.168.52.0 - 192.168.52.127",
+			"192.168.53.0 - 192.168.53.127",
+		}))
+	})
+
+	It("passes the director address and its subnet to create-env", func() {
+		controller.DeployBosh()
+
+		vars := networkVars()
+		Expect(vars["internal_ip"]).To(Equal("172.20.0.2"))
+		Expect(vars["internal_cidr"]).To(Equal("172.20.0.0/24"))
+		Expect(vars["internal_gw"]).To(Equal("172.20.0.1"))
+		Expect(vars["cf_subnet_range"]).To(Equal("192.168.0.0/16"))
+		Expect(fakeClient.CreateArgsForCall(0).Env).To(ContainElement("BOSH_DIRECTOR_IP=172.20.0.2"))
+	})
+
+	It("fails before creating a container for clashing addresses", func() {
+		controller.Config.BoshDirectorIP = "192.168.60.2"
+
+		Expect(controller.DeployBosh()).To(MatchError(ContainSubstring("must not be in the cf subnet 192.168.0.0/16")))
+		Expect(fakeClient.CreateCallCount()).To(Equal(0))
+	})
+})
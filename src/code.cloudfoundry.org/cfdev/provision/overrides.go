@@ -0,0 +1,254 @@
+package provision
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/garden"
+)
+
+const (
+	BoshDeployment = "bosh"
+	CFDeployment   = "cf"
+
+	overridesDir          = "overrides"
+	containerOverridesDir = "/tmp/cfdev-overrides"
+)
+
+// A target needs at least two characters so that windows drive letters are
+// read as part of the path
+var opsTargetPattern = regexp.MustCompile(`^([a-z0-9][a-z0-9-]+):(.+)$`)
+
+// Overrides are the ops files and variables the user applies on top of the
+// manifests of the deps iso. Ops files belong to a deployment, variables are
+// passed to every deployment.
+type Overrides struct {
+	OpsFiles  map[string][]string
+	VarsFiles []string
+	Vars      map[string]string
+
+	// Ops are generated by cf dev and applied before the user's ops files
+	Ops []Ops
+	// TrustedCerts are the PEM encoded CA certs every deployment trusts
+	TrustedCerts []string
+}
+
+type Ops struct {
+	Deployment string
+	Name       string
+	Contents   []byte
+}
+
+// ParseOverrides reads the ops file, vars file and variable flags. Ops files
+// apply to the cf deployment unless prefixed by a deployment name, ie.
+// bosh:director-ops.yml or cf-mysql:mysql-ops.yml.
+func ParseOverrides(opsFiles, varsFiles, vars []string) (Overrides, error) {
+	o := Overrides{
+		OpsFiles: map[string][]string{},
+		Vars:     map[string]string{},
+	}
+
+	for _, opsFile := range opsFiles {
+		deployment, path := CFDeployment, opsFile
+		if m := opsTargetPattern.FindStringSubmatch(opsFile); m != nil {
+			deployment, path = m[1], m[2]
+		}
+		if _, err := os.Stat(path); err != nil {
+			return Overrides{}, fmt.Errorf("ops file not found: %s", path)
+		}
+		o.OpsFiles[deployment] = append(o.OpsFiles[deployment], path)
+	}
+
+	for _, varsFile := range varsFiles {
+		if _, err := os.Stat(varsFile); err != nil {
+			return Overrides{}, fmt.Errorf("vars file not found: %s", varsFile)
+		}
+		o.VarsFiles = append(o.VarsFiles, varsFile)
+	}
+
+	for _, v := range vars {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return Overrides{}, fmt.Errorf("invalid variable '%s': use key=value", v)
+		}
+		o.Vars[parts[0]] = parts[1]
+	}
+
+	return o, nil
+}
+
+// CheckDeployments rejects ops files for deployments other than bosh, cf and
+// the given ones, as a misspelled deployment would never apply them.
+func (o Overrides) CheckDeployments(deployments []string) error {
+	known := map[string]bool{BoshDeployment: true, CFDeployment: true}
+	for _, deployment := range deployments {
+		known[deployment] = true
+	}
+
+	var unknown []string
+	for deployment := range o.OpsFiles {
+		if !known[deployment] {
+			unknown = append(unknown, deployment)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	var names []string
+	for deployment := range known {
+		names = append(names, deployment)
+	}
+	sort.Strings(unknown)
+	sort.Strings(names)
+	return fmt.Errorf("ops files target unknown deployments: %s. Use one of: %s", strings.Join(unknown, ", "), strings.Join(names, ", "))
+}
+
+// SaveOverrides copies the ops and vars files into the state dir, so that a
+// resumed start or a later service deploy applies the same files.
+func SaveOverrides(stateDir string, o Overrides) error {
+	dir := filepath.Join(stateDir, overridesDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	index := map[string]int{}
+	opsPath := func(deployment, name string) string {
+		index[deployment]++
+		return filepath.Join(dir, "ops", deployment, fmt.Sprintf("%03d-%s", index[deployment]-1, name))
+	}
+
+	for _, ops := range o.Ops {
+		path := opsPath(ops.Deployment, ops.Name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, ops.Contents, 0644); err != nil {
+			return err
+		}
+	}
+
+	for deployment, opsFiles := range o.OpsFiles {
+		for _, opsFile := range opsFiles {
+			if err := copyOverride(opsFile, opsPath(deployment, filepath.Base(opsFile))); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, varsFile := range o.VarsFiles {
+		if err := copyOverride(varsFile, filepath.Join(dir, "vars", fmt.Sprintf("%03d-%s", i, filepath.Base(varsFile)))); err != nil {
+			return err
+		}
+	}
+
+	vars := map[string]string{}
+	if len(o.TrustedCerts) > 0 {
+		vars["trusted_ca_certs"] = strings.Join(o.TrustedCerts, "\n") + "\n"
+	}
+	for key, value := range o.Vars {
+		vars[key] = value
+	}
+
+	for i, cert := range o.TrustedCerts {
+		path := filepath.Join(dir, "certs", fmt.Sprintf("cfdev-%03d.crt", i))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(cert+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+
+	if len(vars) > 0 {
+		contents, err := yaml.Marshal(vars)
+		if err != nil {
+			return err
+		}
+		// named to sort after the vars files, so the variable flags win
+		path := filepath.Join(dir, "vars", fmt.Sprintf("%03d-vars.yml", len(o.VarsFiles)))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, contents, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyOverride(src, dest string) error {
+	contents, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dest, contents, 0644)
+}
+
+// streamOverrides copies the saved ops files of the deployment and the vars
+// files into the container and returns the env for the deploy script to pick
+// them up, or no env when there is nothing to apply.
+func (c *Controller) streamOverrides(container garden.Container, deployment string) ([]string, error) {
+	dir := filepath.Join(c.Config.StateDir, overridesDir)
+
+	var files []string
+	for _, sub := range []string{filepath.Join("ops", deployment), "vars", "certs"} {
+		infos, err := ioutil.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			continue
+		}
+		for _, info := range infos {
+			if !info.IsDir() {
+				files = append(files, filepath.Join(sub, info.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Strings(files)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, file := range files {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.ToSlash(file)
+		if strings.HasPrefix(name, "ops/") {
+			name = "ops/" + filepath.Base(file)
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(contents); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := container.StreamIn(garden.StreamInSpec{
+		Path:      containerOverridesDir,
+		User:      "root",
+		TarStream: &buf,
+	}); err != nil {
+		return nil, errors.SafeWrap(err, fmt.Sprintf("failed to copy the ops and vars files for %s", deployment))
+	}
+	return []string{"OVERRIDES_DIR=" + containerOverridesDir}, nil
+}
@@ -0,0 +1,151 @@
+package provision_test
+
+import (
+	"archive/tar"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/provision"
+	"code.cloudfoundry.org/garden/gardenfakes"
+)
+
+var _ = Describe("overrides", func() {
+	var (
+		tmpDir   string
+		stateDir string
+		opsFile  string
+		varsFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "overrides")
+		Expect(err).NotTo(HaveOccurred())
+		stateDir = filepath.Join(tmpDir, "state")
+
+		opsFile = filepath.Join(tmpDir, "ops.yml")
+		Expect(ioutil.WriteFile(opsFile, []byte("- type: remove\n  path: /some\n"), 0644)).To(Succeed())
+		varsFile = filepath.Join(tmpDir, "vars.yml")
+		Expect(ioutil.WriteFile(varsFile, []byte("cell_memory: 8192\n"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("ParseOverrides", func() {
+		It("applies ops files to cf unless they name a deployment", func() {
+			o, err := provision.ParseOverrides(
+				[]string{opsFile, "bosh:" + opsFile, "cf-mysql:" + opsFile},
+				[]string{varsFile},
+				[]string{"key=some=value"},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o.OpsFiles).To(Equal(map[string][]string{
+				"cf":       {opsFile},
+				"bosh":     {opsFile},
+				"cf-mysql": {opsFile},
+			}))
+			Expect(o.VarsFiles).To(Equal([]string{varsFile}))
+			Expect(o.Vars).To(Equal(map[string]string{"key": "some=value"}))
+		})
+
+		It("rejects missing files", func() {
+			_, err := provision.ParseOverrides([]string{"bosh:/no/such/ops.yml"}, nil, nil)
+			Expect(err).To(MatchError("ops file not found: /no/such/ops.yml"))
+
+			_, err = provision.ParseOverrides(nil, []string{"/no/such/vars.yml"}, nil)
+			Expect(err).To(MatchError("vars file not found: /no/such/vars.yml"))
+		})
+
+		It("rejects variables without a value", func() {
+			_, err := provision.ParseOverrides(nil, nil, []string{"key"})
+			Expect(err).To(MatchError("invalid variable 'key': use key=value"))
+		})
+	})
+
+	Describe("CheckDeployments", func() {
+		It("accepts ops files for bosh, cf and the given deployments", func() {
+			o, err := provision.ParseOverrides([]string{opsFile, "bosh:" + opsFile, "cf-mysql:" + opsFile}, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(o.CheckDeployments([]string{"cf-mysql"})).To(Succeed())
+		})
+
+		It("rejects ops files for unknown deployments", func() {
+			o, err := provision.ParseOverrides([]string{"cf-deploymnt:" + opsFile, "cf-mysql:" + opsFile}, nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(o.CheckDeployments([]string{"cf-mysql"})).To(MatchError(
+				"ops files target unknown deployments: cf-deploymnt. Use one of: bosh, cf, cf-mysql",
+			))
+		})
+	})
+
+	Describe("streaming the saved overrides into the deploy container", func() {
+		var (
+			fakeClient    *gardenfakes.FakeClient
+			fakeContainer *gardenfakes.FakeContainer
+			controller    *provision.Controller
+		)
+
+		BeforeEach(func() {
+			o, err := provision.ParseOverrides([]string{"bosh:" + opsFile}, []string{varsFile}, []string{"key=value"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(provision.SaveOverrides(stateDir, o)).To(Succeed())
+
+			fakeContainer = new(gardenfakes.FakeContainer)
+			fakeContainer.RunReturns(nil, errors.New("some error"))
+			fakeClient = new(gardenfakes.FakeClient)
+			fakeClient.CreateReturns(fakeContainer, nil)
+			controller = &provision.Controller{Client: fakeClient, Config: config.Config{StateDir: stateDir}}
+		})
+
+		It("streams the ops files of the deployment and the vars files", func() {
+			controller.DeployBosh()
+
+			Expect(fakeContainer.StreamInCallCount()).To(Equal(1))
+			spec := fakeContainer.StreamInArgsForCall(0)
+			Expect(spec.Path).To(Equal("/tmp/cfdev-overrides"))
+
+			var names []string
+			tr := tar.NewReader(spec.TarStream)
+			for {
+				hdr, err := tr.Next()
+				if err != nil {
+					break
+				}
+				names = append(names, hdr.Name)
+			}
+			Expect(names).To(Equal([]string{"ops/000-ops.yml", "vars/000-vars.yml", "vars/001-vars.yml"}))
+
+			processSpec, _ := fakeContainer.RunArgsForCall(0)
+			Expect(processSpec.Env).To(Equal([]string{"OVERRIDES_DIR=/tmp/cfdev-overrides"}))
+		})
+
+		It("leaves out the ops files of other deployments", func() {
+			controller.DeployCloudFoundry(nil, "")
+
+			spec := fakeContainer.StreamInArgsForCall(0)
+			tr := tar.NewReader(spec.TarStream)
+			hdr, err := tr.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hdr.Name).To(Equal("vars/000-vars.yml"))
+		})
+
+		It("streams nothing without saved overrides", func() {
+			controller.Config.StateDir = filepath.Join(tmpDir, "other-state")
+			controller.DeployService("deploy-mysql", "bin/deploy-mysql", "cf-mysql")
+
+			Expect(fakeContainer.StreamInCallCount()).To(Equal(0))
+			processSpec, _ := fakeContainer.RunArgsForCall(0)
+			Expect(processSpec.Env).To(BeEmpty())
+		})
+	})
+})
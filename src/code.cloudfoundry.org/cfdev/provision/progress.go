@@ -2,12 +2,14 @@ package provision
 
 import (
 	"io"
+	"strings"
 	"time"
 
 	"fmt"
 
 	"code.cloudfoundry.org/cfdev/bosh"
 	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/multilinewriter"
 	"code.cloudfoundry.org/cfdev/singlelinewriter"
 )
 
@@ -16,7 +18,17 @@ type UI interface {
 	Writer() io.Writer
 }
 
-func (c *Controller) DeployServices(ui UI, services []Service) error {
+// DeployServices deploys the services in the order of their dependencies,
+// running services with the parallel hint next to each other, and calls
+// deployed after each service finished. Once a service fails no further
+// services are started, the running ones are waited for and every failure is
+// returned.
+func (c *Controller) DeployServices(ui UI, services []Service, deployed func(Service) error) error {
+	scheduler, err := NewScheduler(services, MaxParallelDeploys)
+	if err != nil {
+		return err
+	}
+
 	config, err := c.FetchBOSHConfig()
 	if err != nil {
 		return err
@@ -27,50 +39,96 @@ func (c *Controller) DeployServices(ui UI, services []Service) error {
 		return err
 	}
 
-	errChan := make(chan error, 1)
+	type result struct {
+		service Service
+		err     error
+	}
+	results := make(chan result, len(services))
+	running := map[string]time.Time{}
+	started := map[string]bool{}
+	var failures []error
+	ui.Say("Deploying %s...", serviceNames(services))
+	lines := multilinewriter.New(ui.Writer())
 
 	for _, service := range services {
-		start := time.Now()
-		ui.Say("Deploying %s...", service.Name)
-
-		go func(handle string, script string) {
-			errChan <- c.DeployService(handle, script)
-		}(service.Handle, service.Script)
+		lines.Say(service.Name, "  %s: Waiting", service.Name)
+	}
 
-		err := c.report(start, ui, b, service, errChan)
-		if err != nil {
-			return err
+	startReady := func() {
+		if len(failures) > 0 {
+			return
+		}
+		for _, service := range scheduler.Next() {
+			running[service.Name] = time.Now()
+			started[service.Name] = true
+			lines.Say(service.Name, "  %s: Deploying", service.Name)
+
+			go func(service Service) {
+				results <- result{service, c.DeployService(service.Handle, service.Script, service.Deployment)}
+			}(service)
 		}
 	}
 
-	return nil
-}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 
-func (c *Controller) report(start time.Time, ui UI, b *bosh.Bosh, service Service, errChan chan error) error {
-	for {
+	startReady()
+	for len(running) > 0 {
 		select {
-		case err := <-errChan:
-			if err != nil {
-				return errors.SafeWrap(err, fmt.Sprintf("Failed to deploy %s", service.Name))
+		case r := <-results:
+			if r.err != nil {
+				lines.Say(r.service.Name, "  %s: Failed", r.service.Name)
+				delete(running, r.service.Name)
+				failures = append(failures, errors.SafeWrap(r.err, fmt.Sprintf("Failed to deploy %s", r.service.Name)))
+				continue
 			}
 
-			ui.Writer().Write([]byte(fmt.Sprintf("\r\033[K  Done (%s)\n", time.Now().Sub(start).Round(time.Second))))
-			return nil
-		default:
-			p := b.GetVMProgress(start, service.Deployment, service.IsErrand)
-
-			switch p.State {
-			case bosh.UploadingReleases:
-				ui.Writer().Write([]byte(fmt.Sprintf("\r\033[K  Uploaded Releases: %d (%s)", p.Releases, p.Duration.Round(time.Second))))
-			case bosh.Deploying:
-				ui.Writer().Write([]byte(fmt.Sprintf("\r\033[K  Progress: %d of %d (%s)", p.Done, p.Total, p.Duration.Round(time.Second))))
-			case bosh.RunningErrand:
-				ui.Writer().Write([]byte(fmt.Sprintf("\r\033[K  Running errand (%s)", p.Duration.Round(time.Second))))
+			lines.Say(r.service.Name, "  %s: Done (%s)", r.service.Name, time.Now().Sub(running[r.service.Name]).Round(time.Second))
+			delete(running, r.service.Name)
+			scheduler.Done(r.service.Name)
+
+			if deployed != nil {
+				if err := deployed(r.service); err != nil {
+					failures = append(failures, err)
+				}
 			}
+			startReady()
+		case <-ticker.C:
+			for _, service := range services {
+				if start, ok := running[service.Name]; ok {
+					reportService(lines, b.GetVMProgress(start, service.Deployment, service.IsErrand), service)
+				}
+			}
+		}
+	}
 
-			time.Sleep(time.Second)
+	if len(failures) == 0 {
+		return nil
+	}
+	for _, service := range services {
+		if !started[service.Name] {
+			lines.Say(service.Name, "  %s: Skipped", service.Name)
 		}
 	}
+	if len(failures) == 1 {
+		return failures[0]
+	}
+	var messages []string
+	for _, failure := range failures {
+		messages = append(messages, "- "+failure.Error())
+	}
+	return errors.SafeWrap(fmt.Errorf("\n%s", strings.Join(messages, "\n")), fmt.Sprintf("%d services failed to deploy", len(failures)))
+}
+
+func reportService(lines *multilinewriter.Writer, p bosh.VMProgress, service Service) {
+	switch p.State {
+	case bosh.UploadingReleases:
+		lines.Say(service.Name, "  %s: Uploaded Releases: %d (%s)", service.Name, p.Releases, p.Duration.Round(time.Second))
+	case bosh.Deploying:
+		lines.Say(service.Name, "  %s: Progress: %d of %d (%s)", service.Name, p.Done, p.Total, p.Duration.Round(time.Second))
+	case bosh.RunningErrand:
+		lines.Say(service.Name, "  %s: Running errand (%s)", service.Name, p.Duration.Round(time.Second))
+	}
 }
 
 func (c *Controller) ReportProgress(ui UI, deploymentName string) {
@@ -0,0 +1,83 @@
+package provision_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/cfdev/provision"
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type discardUI struct{}
+
+func (discardUI) Say(message string, args ...interface{}) {}
+func (discardUI) Writer() io.Writer                       { return ioutil.Discard }
+
+var _ = Describe("DeployServices", func() {
+	var (
+		fakeClient *gardenfakes.FakeClient
+		release    chan struct{}
+		services   []provision.Service
+		deployed   []string
+	)
+
+	BeforeEach(func() {
+		release = make(chan struct{})
+		deployed = nil
+		services = []provision.Service{
+			{Name: "a", Handle: "deploy-a", Parallel: true},
+			{Name: "b", Handle: "deploy-b", Parallel: true},
+			{Name: "c", Handle: "deploy-c", DependsOn: []string{"a"}},
+		}
+
+		fakeClient = new(gardenfakes.FakeClient)
+		fakeClient.CreateStub = func(spec garden.ContainerSpec) (garden.Container, error) {
+			container := new(gardenfakes.FakeContainer)
+			container.RunStub = func(process garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+				switch spec.Handle {
+				case "fetch-bosh-config":
+					return successfulRunStub(process, io)
+				case "deploy-a":
+					p := new(gardenfakes.FakeProcess)
+					p.WaitReturns(1, nil)
+					return p, nil
+				case "deploy-b":
+					p := new(gardenfakes.FakeProcess)
+					p.WaitStub = func() (int, error) {
+						<-release
+						return 0, nil
+					}
+					return p, nil
+				}
+				return nil, errors.New("unexpected deploy of " + spec.Handle)
+			}
+			return container, nil
+		}
+	})
+
+	It("waits for the running deploys and starts no further ones after a failure", func() {
+		done := make(chan error, 1)
+		go func() {
+			done <- (&provision.Controller{Client: fakeClient}).DeployServices(discardUI{}, services, func(service provision.Service) error {
+				deployed = append(deployed, service.Name)
+				return nil
+			})
+		}()
+
+		Consistently(done).ShouldNot(Receive())
+		close(release)
+
+		var err error
+		Eventually(done).Should(Receive(&err))
+		Expect(err).To(MatchError("Failed to deploy a: process exited with status 1"))
+		Expect(deployed).To(Equal([]string{"b"}))
+		for i := 0; i < fakeClient.CreateCallCount(); i++ {
+			Expect(fakeClient.CreateArgsForCall(i).Handle).NotTo(Equal("deploy-c"))
+		}
+	})
+})
@@ -0,0 +1,73 @@
+package provision
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/cfdev/env"
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/garden"
+)
+
+// The proxy env vars are merged into the groups so that the other variables
+// the operator set survive, and an empty value removes them. Nothing is
+// logged as the proxy urls may hold credentials.
+const setProxyEnvScript = `set -e
+cf api "api.${CF_DOMAIN}" --skip-ssl-validation > /dev/null
+cf auth admin admin > /dev/null
+
+for group in running staging; do
+  current="$(cf curl "/v2/config/environment_variable_groups/${group}")"
+  updated="$(jq -c --argjson proxy "${PROXY_ENV}" 'del(.http_proxy, .https_proxy, .no_proxy) + $proxy' <<< "${current}")"
+  cf curl -X PUT "/v2/config/environment_variable_groups/${group}" -d "${updated}" > /dev/null
+done
+`
+
+// SetProxyEnvironment sets the http_proxy, https_proxy and no_proxy variables
+// of the running and staging environment variable groups, so that apps reach
+// the internet through the same proxy as the vm.
+func (c *Controller) SetProxyEnvironment(proxy env.ProxyConfig, systemDomain string) error {
+	handle := "set-proxy-env"
+
+	vars := map[string]string{}
+	if proxy.Http != "" {
+		vars["http_proxy"] = proxy.Http
+	}
+	if proxy.Https != "" {
+		vars["https_proxy"] = proxy.Https
+	}
+	if proxy.NoProxy != "" && len(vars) > 0 {
+		vars["no_proxy"] = proxy.NoProxy
+	}
+	proxyEnv, err := json.Marshal(vars)
+	if err != nil {
+		return err
+	}
+
+	container, err := c.Client.Create(c.containerSpec(handle))
+	if err != nil {
+		return err
+	}
+	defer c.Client.Destroy(handle)
+
+	process, err := container.Run(garden.ProcessSpec{
+		ID:   handle,
+		Path: "/bin/bash",
+		Args: []string{"-c", setProxyEnvScript},
+		User: "root",
+		Env:  []string{"CF_DOMAIN=" + systemDomain, "PROXY_ENV=" + string(proxyEnv)},
+	}, garden.ProcessIO{})
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := process.Wait()
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return errors.SafeWrap(nil, fmt.Sprintf("process exited with status %d", exitCode))
+	}
+	return nil
+}
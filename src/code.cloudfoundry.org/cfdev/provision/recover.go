@@ -0,0 +1,55 @@
+package provision
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/garden"
+)
+
+// The instances of the deployments do not survive a reboot of the vm, the
+// director recreates them from the packages it compiled already
+const recoverDeploymentsScript = `set -e
+export LOG_DIR=/var/vcap/logs
+mkdir -p "${LOG_DIR}"
+exec 1> >(tee -i "${LOG_DIR}/recover-deployments.log")
+exec 2>&1
+
+source /var/vcap/director/env
+
+for deployment in $(bosh deployments --json | jq -r '.Tables[0].Rows[].name'); do
+  bosh --tty --non-interactive --deployment "${deployment}" cloud-check --auto
+done
+`
+
+// RecoverDeployments recreates the missing instances of every deployment,
+// after the vm booted from a snapshot
+func (c *Controller) RecoverDeployments() error {
+	handle := "recover-deployments"
+
+	container, err := c.Client.Create(c.containerSpec(handle))
+	if err != nil {
+		return err
+	}
+	defer c.Client.Destroy(handle)
+
+	process, err := container.Run(garden.ProcessSpec{
+		ID:   handle,
+		Path: "/bin/bash",
+		Args: []string{"-c", recoverDeploymentsScript},
+		User: "root",
+	}, garden.ProcessIO{})
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := process.Wait()
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return errors.SafeWrap(nil, fmt.Sprintf("process exited with status %d", exitCode))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package provision_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cfdev/provision"
+	"code.cloudfoundry.org/garden/gardenfakes"
+)
+
+var _ = Describe("RecoverDeployments", func() {
+	var (
+		fakeClient    *gardenfakes.FakeClient
+		fakeContainer *gardenfakes.FakeContainer
+		fakeProcess   *gardenfakes.FakeProcess
+		controller    *provision.Controller
+	)
+
+	BeforeEach(func() {
+		fakeClient = new(gardenfakes.FakeClient)
+		fakeContainer = new(gardenfakes.FakeContainer)
+		fakeProcess = new(gardenfakes.FakeProcess)
+		fakeClient.CreateReturns(fakeContainer, nil)
+		fakeContainer.RunReturns(fakeProcess, nil)
+		controller = &provision.Controller{Client: fakeClient}
+	})
+
+	It("runs the cloud check of every deployment", func() {
+		Expect(controller.RecoverDeployments()).To(Succeed())
+
+		Expect(fakeClient.CreateArgsForCall(0).Handle).To(Equal("recover-deployments"))
+		spec, _ := fakeContainer.RunArgsForCall(0)
+		Expect(spec.Args[1]).To(ContainSubstring("cloud-check --auto"))
+		Expect(fakeClient.DestroyArgsForCall(0)).To(Equal("recover-deployments"))
+	})
+
+	It("returns an error when the script fails", func() {
+		fakeProcess.WaitReturns(1, nil)
+		Expect(controller.RecoverDeployments()).To(MatchError("process exited with status 1"))
+	})
+})
@@ -0,0 +1,40 @@
+package provision
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/cfdev/config"
+)
+
+const registryMirrorOps = `- type: replace
+  path: /instance_groups/name=diego-cell/jobs/name=garden/properties/garden/docker_registry_endpoint?
+  value: ((registry_mirror))
+`
+
+// AddRegistrySettings makes the cells trust the CAs of the registries and
+// pull through the mirror. Variables the user set take precedence.
+func (o *Overrides) AddRegistrySettings(settings config.StartConfig) error {
+	if o.Vars == nil {
+		o.Vars = map[string]string{}
+	}
+
+	for _, host := range settings.RegistryHosts() {
+		path := settings.RegistrySettings[host].CACert
+		if path == "" {
+			continue
+		}
+		certs, err := ReadCACerts(path)
+		if err != nil {
+			return fmt.Errorf("registry %s: %s", host, err)
+		}
+		o.AddTrustedCerts(certs...)
+	}
+
+	if settings.RegistryMirror != "" {
+		o.Ops = append(o.Ops, Ops{Deployment: CFDeployment, Name: "registry-mirror.yml", Contents: []byte(registryMirrorOps)})
+		if _, ok := o.Vars["registry_mirror"]; !ok {
+			o.Vars["registry_mirror"] = settings.RegistryMirror
+		}
+	}
+	return nil
+}
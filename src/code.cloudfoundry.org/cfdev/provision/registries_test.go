@@ -0,0 +1,76 @@
+package provision_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cfdev/config"
+	"code.cloudfoundry.org/cfdev/provision"
+)
+
+var _ = Describe("AddRegistrySettings", func() {
+	var (
+		tmpDir    string
+		caCert    string
+		overrides provision.Overrides
+		settings  config.StartConfig
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "registries")
+		Expect(err).NotTo(HaveOccurred())
+		caCert = filepath.Join(tmpDir, "ca.pem")
+		Expect(ioutil.WriteFile(caCert, []byte(testCACert), 0644)).To(Succeed())
+
+		overrides = provision.Overrides{}
+		settings = config.StartConfig{StartFile: config.StartFile{
+			RegistrySettings: map[string]config.RegistrySettings{
+				"registry.example.com:5000": {CACert: caCert},
+				"other.example.com":         {},
+			},
+		}}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("trusts the registry CAs", func() {
+		Expect(overrides.AddRegistrySettings(settings)).To(Succeed())
+
+		Expect(overrides.TrustedCerts).To(Equal([]string{strings.TrimSpace(testCACert)}))
+		Expect(overrides.Ops).To(HaveLen(2))
+		Expect(overrides.Ops[0].Deployment).To(Equal("bosh"))
+		Expect(overrides.Ops[1].Deployment).To(Equal("cf"))
+	})
+
+	It("points the cells at the mirror", func() {
+		settings.RegistrySettings = nil
+		settings.RegistryMirror = "https://mirror.example.com"
+		Expect(overrides.AddRegistrySettings(settings)).To(Succeed())
+
+		Expect(overrides.Ops).To(HaveLen(1))
+		Expect(overrides.Ops[0].Deployment).To(Equal("cf"))
+		Expect(overrides.Vars).To(HaveKeyWithValue("registry_mirror", "https://mirror.example.com"))
+	})
+
+	It("keeps the variables set by the user", func() {
+		settings.RegistryMirror = "https://mirror.example.com"
+		overrides.Vars = map[string]string{"registry_mirror": "https://other.example.com"}
+		Expect(overrides.AddRegistrySettings(settings)).To(Succeed())
+		Expect(overrides.Vars).To(HaveKeyWithValue("registry_mirror", "https://other.example.com"))
+	})
+
+	It("rejects CA certs which are not PEM encoded", func() {
+		Expect(ioutil.WriteFile(caCert, []byte("not a cert"), 0644)).To(Succeed())
+		Expect(overrides.AddRegistrySettings(settings)).To(MatchError(
+			"registry registry.example.com:5000: CA cert is not PEM encoded: " + caCert,
+		))
+	})
+})
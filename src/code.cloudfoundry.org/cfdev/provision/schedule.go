@@ -0,0 +1,139 @@
+package provision
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxParallelDeploys bounds how many services are deployed at the same time
+const MaxParallelDeploys = 3
+
+// Scheduler hands out services once the services they depend on are
+// deployed. Dependencies that are not part of the deployment are expected to
+// be deployed already. Services run next to each other only when all of
+// them carry the parallel hint.
+type Scheduler struct {
+	workers int
+	pending []Service
+	running map[string]Service
+	done    map[string]bool
+}
+
+func NewScheduler(services []Service, workers int) (*Scheduler, error) {
+	if err := checkCycles(services); err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	s := &Scheduler{
+		workers: workers,
+		running: map[string]Service{},
+		done:    map[string]bool{},
+	}
+	s.pending = append(s.pending, services...)
+	return s, nil
+}
+
+// Next marks the services which can start now as running and returns them
+func (s *Scheduler) Next() []Service {
+	var next []Service
+	var pending []Service
+
+	blocked := false
+	for _, service := range s.pending {
+		if blocked || !s.ready(service) || !s.fits(service) {
+			pending = append(pending, service)
+			blocked = blocked || (s.ready(service) && !service.Parallel)
+			continue
+		}
+		s.running[service.Name] = service
+		next = append(next, service)
+	}
+	s.pending = pending
+	return next
+}
+
+// Done marks a running service as deployed
+func (s *Scheduler) Done(name string) {
+	delete(s.running, name)
+	s.done[name] = true
+}
+
+func (s *Scheduler) Finished() bool {
+	return len(s.pending) == 0 && len(s.running) == 0
+}
+
+func (s *Scheduler) ready(service Service) bool {
+	for _, dep := range service.DependsOn {
+		if s.done[dep] {
+			continue
+		}
+		if _, ok := s.running[dep]; ok {
+			return false
+		}
+		for _, p := range s.pending {
+			if p.Name == dep {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *Scheduler) fits(service Service) bool {
+	if len(s.running) == 0 {
+		return true
+	}
+	if !service.Parallel || len(s.running) >= s.workers {
+		return false
+	}
+	for _, r := range s.running {
+		if !r.Parallel {
+			return false
+		}
+	}
+	return true
+}
+
+func checkCycles(services []Service) error {
+	byName := map[string]Service{}
+	for _, service := range services {
+		byName[service.Name] = service
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := map[string]int{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf("services depend on each other: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+		marks[name] = visiting
+		for _, dep := range byName[name].DependsOn {
+			if _, ok := byName[dep]; !ok {
+				continue
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		return nil
+	}
+
+	for _, service := range services {
+		if err := visit(service.Name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package provision_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cfdev/provision"
+)
+
+var _ = Describe("Scheduler", func() {
+	names := func(services []provision.Service) []string {
+		result := []string{}
+		for _, service := range services {
+			result = append(result, service.Name)
+		}
+		return result
+	}
+
+	It("deploys services without the parallel hint one after another", func() {
+		scheduler, err := provision.NewScheduler([]provision.Service{
+			{Name: "mysql"},
+			{Name: "redis"},
+		}, 3)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(names(scheduler.Next())).To(Equal([]string{"mysql"}))
+		Expect(scheduler.Next()).To(BeEmpty())
+		scheduler.Done("mysql")
+		Expect(names(scheduler.Next())).To(Equal([]string{"redis"}))
+		scheduler.Done("redis")
+		Expect(scheduler.Finished()).To(BeTrue())
+	})
+
+	It("deploys parallel services together up to the number of workers", func() {
+		scheduler, err := provision.NewScheduler([]provision.Service{
+			{Name: "mysql", Parallel: true},
+			{Name: "redis", Parallel: true},
+			{Name: "rabbitmq", Parallel: true},
+		}, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(names(scheduler.Next())).To(Equal([]string{"mysql", "redis"}))
+		scheduler.Done("redis")
+		Expect(names(scheduler.Next())).To(Equal([]string{"rabbitmq"}))
+	})
+
+	It("waits for the dependencies of a service", func() {
+		scheduler, err := provision.NewScheduler([]provision.Service{
+			{Name: "broker", DependsOn: []string{"mysql"}, Parallel: true},
+			{Name: "mysql", Parallel: true},
+			{Name: "redis", DependsOn: []string{"already-deployed"}, Parallel: true},
+		}, 3)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(names(scheduler.Next())).To(Equal([]string{"mysql", "redis"}))
+		scheduler.Done("mysql")
+		Expect(names(scheduler.Next())).To(Equal([]string{"broker"}))
+	})
+
+	It("does not let later services overtake a waiting service without the parallel hint", func() {
+		scheduler, err := provision.NewScheduler([]provision.Service{
+			{Name: "mysql", Parallel: true},
+			{Name: "redis"},
+			{Name: "rabbitmq", Parallel: true},
+		}, 3)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(names(scheduler.Next())).To(Equal([]string{"mysql"}))
+		scheduler.Done("mysql")
+		Expect(names(scheduler.Next())).To(Equal([]string{"redis"}))
+		scheduler.Done("redis")
+		Expect(names(scheduler.Next())).To(Equal([]string{"rabbitmq"}))
+	})
+
+	It("rejects services which depend on each other", func() {
+		_, err := provision.NewScheduler([]provision.Service{
+			{Name: "mysql", DependsOn: []string{"broker"}},
+			{Name: "broker", DependsOn: []string{"mysql"}},
+		}, 3)
+		Expect(err).To(MatchError("services depend on each other: mysql -> broker -> mysql"))
+	})
+})
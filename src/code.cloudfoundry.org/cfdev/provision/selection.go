@@ -0,0 +1,145 @@
+package provision
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	AllServices = "all"
+	NoServices  = "none"
+
+	selectionFile = "services.json"
+)
+
+// SelectServices picks the services named by the user out of the ones the
+// deps iso offers, together with the services they depend on; no names or
+// "all" selects every service, "none" selects none of them.
+func SelectServices(available []Service, names []string) ([]Service, error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+	if len(names) == 1 && strings.EqualFold(names[0], AllServices) {
+		return available, nil
+	}
+	if len(names) == 1 && strings.EqualFold(names[0], NoServices) {
+		return []Service{}, nil
+	}
+
+	services := []Service{}
+	for _, name := range names {
+		if strings.EqualFold(name, AllServices) || strings.EqualFold(name, NoServices) {
+			return nil, fmt.Errorf("'%s' cannot be combined with other services", name)
+		}
+
+		found := false
+		for _, service := range available {
+			if strings.EqualFold(service.Name, name) {
+				services = append(services, service)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown service '%s' in the services to deploy, available services: %s", name, serviceNames(available))
+		}
+	}
+	return withDependencies(available, services), nil
+}
+
+func withDependencies(available []Service, services []Service) []Service {
+	selected := map[string]bool{}
+	for _, service := range services {
+		selected[service.Name] = true
+	}
+
+	for i := 0; i < len(services); i++ {
+		for _, dep := range services[i].DependsOn {
+			for _, service := range available {
+				if service.Name == dep && !selected[dep] {
+					selected[dep] = true
+					services = append(services, service)
+				}
+			}
+		}
+	}
+	return services
+}
+
+// SaveSelection records the names of the services chosen at start in the
+// state dir.
+func SaveSelection(stateDir string, services []Service) error {
+	names := []string{}
+	for _, service := range services {
+		names = append(names, service.Name)
+	}
+
+	contents, err := json.Marshal(struct {
+		Services []string `json:"services"`
+	}{names})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(stateDir, selectionFile), contents, 0644)
+}
+
+// LoadSelection returns the names of the services chosen at start and
+// whether a choice was recorded at all.
+func LoadSelection(stateDir string) ([]string, bool) {
+	contents, err := ioutil.ReadFile(filepath.Join(stateDir, selectionFile))
+	if err != nil {
+		return nil, false
+	}
+
+	var selection struct {
+		Services []string `json:"services"`
+	}
+	if err := json.Unmarshal(contents, &selection); err != nil {
+		return nil, false
+	}
+	return selection.Services, true
+}
+
+// UpdateSelection adds a service to or removes it from the recorded
+// selection; without a recorded selection every available service counts as
+// selected.
+func UpdateSelection(stateDir string, available []Service, name string, selected bool) error {
+	names, recorded := LoadSelection(stateDir)
+	if !recorded {
+		for _, service := range available {
+			names = append(names, service.Name)
+		}
+	}
+
+	var services []Service
+	for _, service := range available {
+		if strings.EqualFold(service.Name, name) {
+			if selected {
+				services = append(services, service)
+			}
+			continue
+		}
+		for _, n := range names {
+			if strings.EqualFold(service.Name, n) {
+				services = append(services, service)
+				break
+			}
+		}
+	}
+	return SaveSelection(stateDir, services)
+}
+
+func serviceNames(services []Service) string {
+	var names []string
+	for _, service := range services {
+		names = append(names, service.Name)
+	}
+	return strings.Join(names, ", ")
+}
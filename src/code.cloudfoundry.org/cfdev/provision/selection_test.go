@@ -0,0 +1,94 @@
+package provision_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cfdev/provision"
+)
+
+var _ = Describe("service selection", func() {
+	var available []provision.Service
+
+	BeforeEach(func() {
+		available = []provision.Service{
+			{Name: "Mysql", Deployment: "cf-mysql"},
+			{Name: "Redis", Deployment: "cf-redis"},
+		}
+	})
+
+	Describe("SelectServices", func() {
+		It("selects every service when no names are given", func() {
+			Expect(provision.SelectServices(available, nil)).To(Equal(available))
+		})
+
+		It("selects every service for all", func() {
+			Expect(provision.SelectServices(available, []string{"all"})).To(Equal(available))
+		})
+
+		It("selects no service for none", func() {
+			Expect(provision.SelectServices(available, []string{"none"})).To(BeEmpty())
+		})
+
+		It("selects services by name in the given order", func() {
+			Expect(provision.SelectServices(available, []string{"redis", "mysql"})).To(Equal([]provision.Service{
+				available[1], available[0],
+			}))
+		})
+
+		It("selects the services a selected service depends on", func() {
+			available = append(available, provision.Service{Name: "Broker", DependsOn: []string{"Mysql"}})
+			Expect(provision.SelectServices(available, []string{"broker"})).To(Equal([]provision.Service{
+				available[2], available[0],
+			}))
+		})
+
+		It("rejects unknown services", func() {
+			_, err := provision.SelectServices(available, []string{"rabbitmq"})
+			Expect(err).To(MatchError("unknown service 'rabbitmq' in the services to deploy, available services: Mysql, Redis"))
+		})
+
+		It("rejects none combined with other services", func() {
+			_, err := provision.SelectServices(available, []string{"mysql", "none"})
+			Expect(err).To(MatchError("'none' cannot be combined with other services"))
+		})
+	})
+
+	Describe("SaveSelection", func() {
+		var stateDir string
+
+		BeforeEach(func() {
+			var err error
+			stateDir, err = ioutil.TempDir("", "state-dir")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(stateDir)
+		})
+
+		It("records the names for LoadSelection", func() {
+			_, recorded := provision.LoadSelection(stateDir)
+			Expect(recorded).To(BeFalse())
+
+			Expect(provision.SaveSelection(stateDir, available[:1])).To(Succeed())
+
+			names, recorded := provision.LoadSelection(stateDir)
+			Expect(recorded).To(BeTrue())
+			Expect(names).To(Equal([]string{"Mysql"}))
+		})
+
+		It("adds and removes services with UpdateSelection", func() {
+			Expect(provision.UpdateSelection(stateDir, available, "mysql", false)).To(Succeed())
+			names, _ := provision.LoadSelection(stateDir)
+			Expect(names).To(Equal([]string{"Redis"}))
+
+			Expect(provision.UpdateSelection(stateDir, available, "mysql", true)).To(Succeed())
+			names, _ = provision.LoadSelection(stateDir)
+			Expect(names).To(Equal([]string{"Mysql", "Redis"}))
+		})
+	})
+})
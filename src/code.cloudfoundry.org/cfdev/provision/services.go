@@ -12,8 +12,14 @@ import (
 	"code.cloudfoundry.org/garden"
 )
 
-func (c *Controller) DeployService(handle, script string) error {
-	container, err := c.Client.Create(containerSpec(handle))
+func (c *Controller) DeployService(handle, script, deployment string) error {
+	container, err := c.createContainer(c.containerSpec(handle))
+	if err != nil {
+		return err
+	}
+	defer c.Client.Destroy(handle)
+
+	env, err := c.streamOverrides(container, deployment)
 	if err != nil {
 		return err
 	}
@@ -23,6 +29,7 @@ func (c *Controller) DeployService(handle, script string) error {
 		Path: "/bin/bash",
 		Args: []string{fmt.Sprintf("/var/vcap/cache/%s", script)},
 		User: "root",
+		Env:  env,
 	}, garden.ProcessIO{})
 
 	if err != nil {
@@ -38,21 +45,21 @@ func (c *Controller) DeployService(handle, script string) error {
 		return errors.SafeWrap(nil, fmt.Sprintf("process exited with status %d", exitCode))
 	}
 
-	c.Client.Destroy(handle)
-
 	return nil
 }
 
 type Service struct {
-	Name       string `yaml:"name"`
-	Handle     string `yaml:"handle"`
-	Script     string `yaml:"script"`
-	Deployment string `yaml:"deployment"`
-	IsErrand   bool   `yaml:"errand"`
+	Name       string   `yaml:"name"`
+	Handle     string   `yaml:"handle"`
+	Script     string   `yaml:"script"`
+	Deployment string   `yaml:"deployment"`
+	IsErrand   bool     `yaml:"errand"`
+	DependsOn  []string `yaml:"depends_on"`
+	Parallel   bool     `yaml:"parallel"`
 }
 
 func (c *Controller) GetServices() ([]Service, string, error) {
-	container, err := c.Client.Create(containerSpec("get-services"))
+	container, err := c.Client.Create(c.containerSpec("get-services"))
 	if err != nil {
 		return nil, "", err
 	}
@@ -85,11 +92,11 @@ func (c *Controller) GetServices() ([]Service, string, error) {
 	return nil, "", fmt.Errorf("metadata.yml not found in container")
 }
 
-func containerSpec(handle string) garden.ContainerSpec {
+func (c *Controller) containerSpec(handle string) garden.ContainerSpec {
 	return garden.ContainerSpec{
 		Handle:     handle,
 		Privileged: true,
-		Network:    "10.246.0.0/16",
+		Network:    c.containerNetwork(),
 		Image: garden.ImageRef{
 			URI: "/var/vcap/cache/workspace.tar",
 		},
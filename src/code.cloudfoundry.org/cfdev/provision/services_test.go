@@ -25,7 +25,7 @@ var _ = Describe("DeployMysql", func() {
 	})
 
 	JustBeforeEach(func() {
-		err = gclient.DeployService("deploy-mysql", "bin/deploy-mysql")
+		err = gclient.DeployService("deploy-mysql", "bin/deploy-mysql", "cf-mysql")
 	})
 
 	It("creates a container", func() {
@@ -90,9 +90,8 @@ var _ = Describe("DeployMysql", func() {
 			})
 
 			It("deletes the container", func() {
-				Expect(fakeClient.DestroyCallCount()).To(Equal(1))
-				handle := fakeClient.DestroyArgsForCall(0)
-				Expect(handle).To(Equal("deploy-mysql"))
+				Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-mysql"))
 			})
 		})
 
@@ -116,6 +115,21 @@ var _ = Describe("DeployMysql", func() {
 			It("returns an error", func() {
 				Expect(err).To(MatchError("process exited with status 23"))
 			})
+
+			It("deletes the container", func() {
+				Expect(fakeClient.DestroyCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyArgsForCall(1)).To(Equal("deploy-mysql"))
+			})
+
+			It("can be retried", func() {
+				process := new(gardenfakes.FakeProcess)
+				process.WaitReturns(0, nil)
+				fakeContainer.RunReturns(process, nil)
+
+				Expect(gclient.DeployService("deploy-mysql", "bin/deploy-mysql", "cf-mysql")).To(Succeed())
+				Expect(fakeClient.CreateCallCount()).To(Equal(2))
+				Expect(fakeClient.DestroyCallCount()).To(Equal(4))
+			})
 		})
 
 		Context("when we cannot determine the state of the deploy", func() {
@@ -139,5 +153,10 @@ var _ = Describe("DeployMysql", func() {
 		It("forwards the error", func() {
 			Expect(err).To(MatchError("unable to create container"))
 		})
+
+		It("first removes a container left behind by an earlier run", func() {
+			Expect(fakeClient.DestroyCallCount()).To(Equal(1))
+			Expect(fakeClient.DestroyArgsForCall(0)).To(Equal("deploy-mysql"))
+		})
 	})
 })
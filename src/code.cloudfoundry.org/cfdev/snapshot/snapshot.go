@@ -0,0 +1,221 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cfdev/errors"
+	"code.cloudfoundry.org/cfdev/resource"
+	"code.cloudfoundry.org/cfdev/util"
+)
+
+const (
+	snapshotsDir = "snapshots"
+	metadataFile = "snapshot.json"
+	stateDir     = "state"
+)
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// runtimeFiles belong to the running vm and are recreated by the next start
+var runtimeFiles = []string{".pid", ".sock", ".log"}
+
+// Metadata ties a snapshot to the deps iso the environment was deployed
+// from, a snapshot only restores on top of the same iso.
+type Metadata struct {
+	Name         string    `json:"name"`
+	CreatedAt    time.Time `json:"created_at"`
+	DepsIsoPath  string    `json:"deps_iso_path"`
+	DepsIsoMD5   string    `json:"deps_iso_md5"`
+	SystemDomain string    `json:"system_domain,omitempty"`
+	Disk         string    `json:"disk"`
+	DiskBytes    int64     `json:"disk_bytes"`
+}
+
+// Store keeps the snapshots of an environment in its env dir, which cf dev
+// start and stop leave alone
+type Store struct {
+	Dir string
+}
+
+func New(envDir string) *Store {
+	return &Store{Dir: filepath.Join(envDir, snapshotsDir)}
+}
+
+// Save copies the vm disk and the state dir into the named snapshot. The vm
+// has to be stopped so that the disk is consistent.
+func (s *Store) Save(name, stateDirPath, diskPath string) (Metadata, error) {
+	if !namePattern.MatchString(name) {
+		return Metadata{}, fmt.Errorf("invalid snapshot name '%s': use letters, digits, dots, dashes and underscores", name)
+	}
+	if _, err := os.Stat(s.path(name)); err == nil {
+		return Metadata{}, fmt.Errorf("snapshot %s already exists", name)
+	}
+
+	var cp struct {
+		DepsIsoPath  string `json:"deps_iso_path"`
+		SystemDomain string `json:"system_domain"`
+	}
+	// written by cf dev start
+	contents, err := ioutil.ReadFile(filepath.Join(stateDirPath, "checkpoints.json"))
+	if err != nil || json.Unmarshal(contents, &cp) != nil || cp.DepsIsoPath == "" {
+		return Metadata{}, fmt.Errorf("there is no deployed environment to snapshot")
+	}
+	info, err := os.Stat(diskPath)
+	if err != nil {
+		return Metadata{}, errors.SafeWrap(err, "failed to find the vm disk")
+	}
+	depsIsoMD5, err := resource.MD5(cp.DepsIsoPath)
+	if err != nil {
+		return Metadata{}, errors.SafeWrap(err, "failed to checksum the deps iso")
+	}
+
+	meta := Metadata{
+		Name:         name,
+		CreatedAt:    time.Now().UTC(),
+		DepsIsoPath:  cp.DepsIsoPath,
+		DepsIsoMD5:   depsIsoMD5,
+		SystemDomain: cp.SystemDomain,
+		Disk:         filepath.Base(diskPath),
+		DiskBytes:    info.Size(),
+	}
+
+	err = func() error {
+		if err := copyDir(stateDirPath, filepath.Join(s.path(name), stateDir), diskPath, true); err != nil {
+			return err
+		}
+		if err := util.CopyFile(diskPath, filepath.Join(s.path(name), meta.Disk)); err != nil {
+			return err
+		}
+		// written last, a snapshot without metadata is incomplete
+		contents, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(s.path(name), metadataFile), contents, 0644)
+	}()
+	if err != nil {
+		os.RemoveAll(s.path(name))
+		return Metadata{}, errors.SafeWrap(err, "failed to save the snapshot")
+	}
+	return meta, nil
+}
+
+// Get returns the metadata of a complete snapshot
+func (s *Store) Get(name string) (Metadata, error) {
+	var meta Metadata
+	contents, err := ioutil.ReadFile(filepath.Join(s.path(name), metadataFile))
+	if err != nil {
+		return Metadata{}, fmt.Errorf("snapshot %s does not exist", name)
+	}
+	if err := json.Unmarshal(contents, &meta); err != nil {
+		return Metadata{}, errors.SafeWrap(err, fmt.Sprintf("failed to read snapshot %s", name))
+	}
+	return meta, nil
+}
+
+// Verify checks that the deps iso of the snapshot is still the one it was
+// deployed from
+func (s *Store) Verify(meta Metadata) error {
+	depsIsoMD5, err := resource.MD5(meta.DepsIsoPath)
+	if err != nil {
+		return fmt.Errorf("snapshot %s needs the deps iso at %s", meta.Name, meta.DepsIsoPath)
+	}
+	if depsIsoMD5 != meta.DepsIsoMD5 {
+		return fmt.Errorf("the deps iso at %s changed since snapshot %s was saved", meta.DepsIsoPath, meta.Name)
+	}
+	return nil
+}
+
+// Restore copies the disk and the state of the snapshot back. State files
+// which exist already, such as the vm definition, are kept.
+func (s *Store) Restore(meta Metadata, stateDirPath, diskPath string) error {
+	if err := copyDir(filepath.Join(s.path(meta.Name), stateDir), stateDirPath, "", false); err != nil {
+		return errors.SafeWrap(err, "failed to restore the state")
+	}
+	if err := os.MkdirAll(filepath.Dir(diskPath), 0755); err != nil {
+		return err
+	}
+	if err := util.CopyFile(filepath.Join(s.path(meta.Name), meta.Disk), diskPath); err != nil {
+		return errors.SafeWrap(err, "failed to restore the vm disk")
+	}
+	return nil
+}
+
+// List returns the complete snapshots, oldest first
+func (s *Store) List() ([]Metadata, error) {
+	infos, err := ioutil.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return []Metadata{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	snapshots := []Metadata{}
+	for _, info := range infos {
+		if meta, err := s.Get(info.Name()); err == nil {
+			snapshots = append(snapshots, meta)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+func (s *Store) Delete(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("snapshot %s does not exist", name)
+	}
+	if _, err := os.Stat(s.path(name)); err != nil {
+		return fmt.Errorf("snapshot %s does not exist", name)
+	}
+	return os.RemoveAll(s.path(name))
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.Dir, name)
+}
+
+// copyDir copies the regular files of src, skipping the file at skip and, on
+// save, the runtime files of the vm. Existing files are only replaced when
+// overwrite is set.
+func copyDir(src, dest, skip string, overwrite bool) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() || path == skip || isRuntimeFile(path) {
+			return nil
+		}
+		if _, err := os.Stat(target); err == nil && !overwrite {
+			return nil
+		}
+		return util.CopyFile(path, target)
+	})
+}
+
+func isRuntimeFile(path string) bool {
+	for _, ext := range runtimeFiles {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
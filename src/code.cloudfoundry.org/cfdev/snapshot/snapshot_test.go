@@ -0,0 +1,167 @@
+package snapshot_test
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cfdev/snapshot"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		tmpDir   string
+		stateDir string
+		diskPath string
+		isoPath  string
+		store    *snapshot.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cfdev-snapshot-")
+		Expect(err).NotTo(HaveOccurred())
+
+		stateDir = filepath.Join(tmpDir, "state")
+		diskPath = filepath.Join(stateDir, "disk.qcow2")
+		isoPath = filepath.Join(tmpDir, "cf-deps.iso")
+		Expect(os.MkdirAll(filepath.Join(stateDir, "bosh"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(isoPath, []byte("deps"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(diskPath, []byte("disk"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(stateDir, "bosh", "creds.yml"), []byte("creds"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(stateDir, "qemu.pid"), []byte("123"), 0644)).To(Succeed())
+
+		contents, err := json.Marshal(map[string]interface{}{
+			"deps_iso_path": isoPath,
+			"system_domain": "dev.example.com",
+			"phases":        []string{"vm-created", "cf-deployed"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(stateDir, "checkpoints.json"), contents, 0644)).To(Succeed())
+
+		store = snapshot.New(tmpDir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("Save", func() {
+		It("copies the disk and the state, without the runtime files", func() {
+			meta, err := store.Save("before-upgrade", stateDir, diskPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.Name).To(Equal("before-upgrade"))
+			Expect(meta.DepsIsoPath).To(Equal(isoPath))
+			Expect(meta.DepsIsoMD5).To(Equal(fmt.Sprintf("%x", md5.Sum([]byte("deps")))))
+			Expect(meta.SystemDomain).To(Equal("dev.example.com"))
+			Expect(meta.DiskBytes).To(Equal(int64(4)))
+
+			dir := filepath.Join(tmpDir, "snapshots", "before-upgrade")
+			Expect(ioutil.ReadFile(filepath.Join(dir, "disk.qcow2"))).To(Equal([]byte("disk")))
+			Expect(ioutil.ReadFile(filepath.Join(dir, "state", "bosh", "creds.yml"))).To(Equal([]byte("creds")))
+			Expect(filepath.Join(dir, "state", "disk.qcow2")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(dir, "state", "qemu.pid")).NotTo(BeAnExistingFile())
+
+			Expect(store.Get("before-upgrade")).To(Equal(meta))
+		})
+
+		It("fails for an existing snapshot", func() {
+			_, err := store.Save("before-upgrade", stateDir, diskPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = store.Save("before-upgrade", stateDir, diskPath)
+			Expect(err).To(MatchError("snapshot before-upgrade already exists"))
+		})
+
+		It("fails for an invalid name", func() {
+			_, err := store.Save("../escape", stateDir, diskPath)
+			Expect(err).To(MatchError(ContainSubstring("invalid snapshot name")))
+		})
+
+		It("fails when nothing was deployed", func() {
+			Expect(os.Remove(filepath.Join(stateDir, "checkpoints.json"))).To(Succeed())
+
+			_, err := store.Save("before-upgrade", stateDir, diskPath)
+			Expect(err).To(MatchError("there is no deployed environment to snapshot"))
+		})
+
+		It("leaves no partial snapshot behind", func() {
+			Expect(os.Remove(isoPath)).To(Succeed())
+
+			_, err := store.Save("before-upgrade", stateDir, diskPath)
+			Expect(err).To(HaveOccurred())
+			Expect(store.List()).To(BeEmpty())
+		})
+	})
+
+	Describe("Verify", func() {
+		var meta snapshot.Metadata
+
+		BeforeEach(func() {
+			var err error
+			meta, err = store.Save("before-upgrade", stateDir, diskPath)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("succeeds for the same deps iso", func() {
+			Expect(store.Verify(meta)).To(Succeed())
+		})
+
+		It("fails when the deps iso changed", func() {
+			Expect(ioutil.WriteFile(isoPath, []byte("other deps"), 0644)).To(Succeed())
+			Expect(store.Verify(meta)).To(MatchError(ContainSubstring("changed since snapshot before-upgrade was saved")))
+		})
+
+		It("fails when the deps iso is missing", func() {
+			Expect(os.Remove(isoPath)).To(Succeed())
+			Expect(store.Verify(meta)).To(MatchError("snapshot before-upgrade needs the deps iso at " + isoPath))
+		})
+	})
+
+	Describe("Restore", func() {
+		It("copies the disk and the state back, keeping existing files", func() {
+			meta, err := store.Save("before-upgrade", stateDir, diskPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.RemoveAll(stateDir)).To(Succeed())
+			Expect(os.MkdirAll(stateDir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(stateDir, "vm.json"), []byte("new vm"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(diskPath, []byte("empty"), 0644)).To(Succeed())
+
+			Expect(store.Restore(meta, stateDir, diskPath)).To(Succeed())
+			Expect(ioutil.ReadFile(diskPath)).To(Equal([]byte("disk")))
+			Expect(ioutil.ReadFile(filepath.Join(stateDir, "bosh", "creds.yml"))).To(Equal([]byte("creds")))
+			Expect(ioutil.ReadFile(filepath.Join(stateDir, "vm.json"))).To(Equal([]byte("new vm")))
+		})
+	})
+
+	Describe("List and Delete", func() {
+		It("lists the snapshots oldest first and deletes them", func() {
+			Expect(store.List()).To(BeEmpty())
+
+			_, err := store.Save("first", stateDir, diskPath)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = store.Save("second", stateDir, diskPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			snapshots, err := store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshots).To(HaveLen(2))
+			Expect(snapshots[0].Name).To(Equal("first"))
+			Expect(snapshots[1].Name).To(Equal("second"))
+
+			Expect(store.Delete("first")).To(Succeed())
+			snapshots, err = store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshots).To(HaveLen(1))
+
+			Expect(store.Delete("first")).To(MatchError("snapshot first does not exist"))
+		})
+	})
+})
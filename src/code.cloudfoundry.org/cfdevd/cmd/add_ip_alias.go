@@ -13,7 +13,7 @@ func (u *AddIPAliasCommand) Execute(conn *net.UnixConn) error {
 
 	hostNet := &networkd.HostNetD{}
 
-	err := hostNet.AddLoopbackAliases(BOSH_IP, GOROUTER_IP)
+	err := hostNet.AddLoopbackAliases(AliasIPs...)
 	if err == nil {
 		conn.Write([]byte{0})
 	}else{
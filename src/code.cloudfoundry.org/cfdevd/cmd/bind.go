@@ -1,4 +1,4 @@
-// +build darwin
+// +build darwin linux
 
 package cmd
 
@@ -14,6 +14,20 @@ import (
 const BOSH_IP = "10.245.0.2"
 const GOROUTER_IP = "10.144.0.34"
 
+// AliasIPs are the addresses cfdevd aliases on the loopback interface and lets
+// vpnkit bind. cfdev passes its configured addresses when installing cfdevd.
+var AliasIPs = []string{BOSH_IP, GOROUTER_IP}
+
+func SetAliasIPs(ips []string) error {
+	for _, ip := range ips {
+		if net.ParseIP(ip).To4() == nil {
+			return fmt.Errorf("invalid alias ip: %s", ip)
+		}
+	}
+	AliasIPs = ips
+	return nil
+}
+
 const (
 	ERROR_IN_USE    = uint8(48)
 	ERROR_NOT_AVAIL = uint8(49)
@@ -26,12 +40,8 @@ type BindCommand struct {
 }
 
 func (b *BindCommand) isIPAllowed(ip net.IP) bool {
-	allowedIPs := []net.IP{
-		net.ParseIP(BOSH_IP),
-		net.ParseIP(GOROUTER_IP),
-	}
-	for _, allowed := range allowedIPs {
-		if allowed.Equal(ip) {
+	for _, allowed := range AliasIPs {
+		if net.ParseIP(allowed).Equal(ip) {
 			return true
 		}
 	}
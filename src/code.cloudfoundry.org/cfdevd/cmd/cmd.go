@@ -1,4 +1,4 @@
-// +build darwin
+// +build darwin linux
 
 package cmd
 
@@ -7,7 +7,6 @@ import (
 	"io"
 	"net"
 	"os"
-	"code.cloudfoundry.org/cfdev/daemon"
 )
 
 type Command interface {
@@ -30,7 +29,7 @@ func UnmarshalCommand(conn io.Reader) (Command, error) {
 	case UninstallType:
 
 		return &UninstallCommand{
-			DaemonRunner: daemon.New(""),
+			DaemonRunner: newDaemonRunner(),
 		}, nil
 	case RemoveIPAliasType:
 
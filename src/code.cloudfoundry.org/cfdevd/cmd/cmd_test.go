@@ -1,4 +1,4 @@
-// +build darwin
+// +build darwin linux
 
 package cmd_test
 
@@ -76,4 +76,20 @@ var _ = Describe("cmd", func() {
 			Fail("wrong type!")
 		}
 	})
+
+	Describe("SetAliasIPs", func() {
+		AfterEach(func() {
+			cmd.AliasIPs = []string{cmd.BOSH_IP, cmd.GOROUTER_IP}
+		})
+
+		It("replaces the addresses cfdevd aliases and allows binding", func() {
+			Expect(cmd.SetAliasIPs([]string{"172.20.0.2", "172.21.0.34"})).To(Succeed())
+			Expect(cmd.AliasIPs).To(Equal([]string{"172.20.0.2", "172.21.0.34"}))
+		})
+
+		It("rejects addresses that are not IPv4", func() {
+			Expect(cmd.SetAliasIPs([]string{"172.20.0.2", "not-an-ip"})).To(MatchError("invalid alias ip: not-an-ip"))
+			Expect(cmd.AliasIPs).To(Equal([]string{cmd.BOSH_IP, cmd.GOROUTER_IP}))
+		})
+	})
 })
@@ -0,0 +1,7 @@
+package cmd
+
+import "code.cloudfoundry.org/cfdev/daemon"
+
+func newDaemonRunner() DaemonRunner {
+	return daemon.New("")
+}
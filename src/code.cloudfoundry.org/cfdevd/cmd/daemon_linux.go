@@ -0,0 +1,12 @@
+package cmd
+
+import "code.cloudfoundry.org/cfdev/daemon"
+
+const ProcessStateDir = "/var/lib/cfdevd"
+
+func newDaemonRunner() DaemonRunner {
+	if daemon.SystemSystemdAvailable() {
+		return daemon.NewSystemSystemd()
+	}
+	return &daemon.Process{StateDir: ProcessStateDir}
+}
@@ -1,18 +1,20 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"io"
 
 	"code.cloudfoundry.org/cfdevd/cmd"
-	"code.cloudfoundry.org/cfdev/daemon"
+	_ "code.cloudfoundry.org/cfdev/daemon/supervise"
 )
 
 const SockName = "ListenSocket"
@@ -41,30 +43,6 @@ func registerSignalHandler() {
 	}(sigc)
 }
 
-func install(programSrc string) {
-	lctl := daemon.New("")
-	program := "/Library/PrivilegedHelperTools/org.cloudfoundry.cfdevd"
-	cfdevdSpec := daemon.DaemonSpec{
-		Label:   "org.cloudfoundry.cfdevd",
-		Program: program,
-		ProgramArguments: []string{
-			program,
-		},
-		RunAtLoad: false,
-		Sockets: map[string]string{
-			SockName: "/var/tmp/cfdevd.socket",
-		},
-		StdoutPath: "/var/tmp/cfdevd.stdout.log",
-		StderrPath: "/var/tmp/cfdevd.stderr.log",
-	}
-	if err := copyExecutable(programSrc, program); err != nil {
-		fmt.Println("Failed to copy cfdevd: ", err)
-	}
-	if err := lctl.AddDaemon(cfdevdSpec); err != nil {
-		fmt.Println("Failed to install cfdevd: ", err)
-	}
-}
-
 func copyExecutable(src string, dest string) error {
 	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
 		return err
@@ -88,27 +66,11 @@ func copyExecutable(src string, dest string) error {
 	return err
 }
 
-func uninstall(prog string) {
-	lctl := daemon.New("")
-	program := "/Library/PrivilegedHelperTools/org.cloudfoundry.cfdevd"
-	if err := lctl.RemoveDaemon("org.cloudfoundry.cfdevd"); err != nil {
-		fmt.Println("Failed to uninstall cfdevd: ", err)
-	}
-	if err := os.Remove(program); err != nil {
-		fmt.Println("Failed to delete installed cfdevd:", err)
-	}
-}
-
 func run() {
 	registerSignalHandler()
-	listeners, err := daemon.Listeners(SockName)
-	if err != nil || len(listeners) != 1 {
-		log.Fatal("Failed to obtain socket from launchd")
-	}
-	listener, ok := listeners[0].(*net.UnixListener)
-	if !ok {
-		log.Fatal("Failed to cast listener to unix listener")
-
+	listener, err := listen()
+	if err != nil {
+		log.Fatal(err)
 	}
 	for {
 		conn, err := listener.AcceptUnix()
@@ -120,17 +82,32 @@ func run() {
 	}
 }
 
+// daemonArgs are the arguments the installed daemon is started with
+func daemonArgs() []string {
+	return []string{"--alias-ips", strings.Join(cmd.AliasIPs, ",")}
+}
+
 func main() {
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "install":
-			install(os.Args[0])
-		case "uninstall":
-			uninstall(os.Args[0])
-		default:
-			log.Fatal("unrecognized command ", os.Args[1])
-		}
-	} else {
+	command, args := "", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("cfdevd", flag.ExitOnError)
+	aliasIPs := flags.String("alias-ips", strings.Join(cmd.AliasIPs, ","), "addresses to alias on the loopback interface and allow vpnkit to bind")
+	flags.Parse(args)
+	if err := cmd.SetAliasIPs(strings.Split(*aliasIPs, ",")); err != nil {
+		log.Fatal(err)
+	}
+
+	switch command {
+	case "install":
+		install(os.Args[0])
+	case "uninstall":
+		uninstall(os.Args[0])
+	case "":
 		run()
+	default:
+		log.Fatal("unrecognized command ", command)
 	}
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"code.cloudfoundry.org/cfdev/daemon"
+)
+
+func install(programSrc string) {
+	lctl := daemon.New("")
+	program := "/Library/PrivilegedHelperTools/org.cloudfoundry.cfdevd"
+	cfdevdSpec := daemon.DaemonSpec{
+		Label:            "org.cloudfoundry.cfdevd",
+		Program:          program,
+		ProgramArguments: append([]string{program}, daemonArgs()...),
+		RunAtLoad:        false,
+		Sockets: map[string]string{
+			SockName: "/var/tmp/cfdevd.socket",
+		},
+		StdoutPath: "/var/tmp/cfdevd.stdout.log",
+		StderrPath: "/var/tmp/cfdevd.stderr.log",
+	}
+	if err := copyExecutable(programSrc, program); err != nil {
+		fmt.Println("Failed to copy cfdevd: ", err)
+	}
+	if err := lctl.AddDaemon(cfdevdSpec); err != nil {
+		fmt.Println("Failed to install cfdevd: ", err)
+	}
+}
+
+func uninstall(prog string) {
+	lctl := daemon.New("")
+	program := "/Library/PrivilegedHelperTools/org.cloudfoundry.cfdevd"
+	if err := lctl.RemoveDaemon("org.cloudfoundry.cfdevd"); err != nil {
+		fmt.Println("Failed to uninstall cfdevd: ", err)
+	}
+	if err := os.Remove(program); err != nil {
+		fmt.Println("Failed to delete installed cfdevd:", err)
+	}
+}
+
+func listen() (*net.UnixListener, error) {
+	listeners, err := daemon.Listeners(SockName)
+	if err != nil || len(listeners) != 1 {
+		return nil, errors.New("Failed to obtain socket from launchd")
+	}
+	listener, ok := listeners[0].(*net.UnixListener)
+	if !ok {
+		return nil, errors.New("Failed to cast listener to unix listener")
+	}
+	return listener, nil
+}
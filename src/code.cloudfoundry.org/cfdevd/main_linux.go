@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"code.cloudfoundry.org/cfdev/daemon"
+	"code.cloudfoundry.org/cfdevd/cmd"
+)
+
+const (
+	label      = "org.cloudfoundry.cfdevd"
+	program    = "/usr/local/libexec/org.cloudfoundry.cfdevd"
+	socketPath = "/var/run/cfdevd.socket"
+)
+
+type daemonRunner interface {
+	AddDaemon(daemon.DaemonSpec) error
+	RemoveDaemon(string) error
+}
+
+func newDaemonRunner() daemonRunner {
+	if daemon.SystemSystemdAvailable() {
+		return daemon.NewSystemSystemd()
+	}
+	return &daemon.Process{
+		StateDir:       cmd.ProcessStateDir,
+		SupervisorPath: program,
+	}
+}
+
+func install(programSrc string) {
+	runner := newDaemonRunner()
+	cfdevdSpec := daemon.DaemonSpec{
+		Label:            label,
+		Program:          program,
+		ProgramArguments: append([]string{program}, daemonArgs()...),
+		Sockets: map[string]string{
+			SockName: socketPath,
+		},
+		StdoutPath: "/var/log/cfdevd.stdout.log",
+		StderrPath: "/var/log/cfdevd.stderr.log",
+	}
+	if _, ok := runner.(*daemon.Process); ok {
+		// without socket activation cfdevd has to be running to bind its socket
+		cfdevdSpec.RunAtLoad = true
+		cfdevdSpec.Restart = daemon.RestartOnFailure
+	}
+
+	runner.RemoveDaemon(label)
+	if err := copyExecutable(programSrc, program); err != nil {
+		fmt.Println("Failed to copy cfdevd: ", err)
+	}
+	if err := runner.AddDaemon(cfdevdSpec); err != nil {
+		fmt.Println("Failed to install cfdevd: ", err)
+	}
+}
+
+func uninstall(prog string) {
+	if err := newDaemonRunner().RemoveDaemon(label); err != nil {
+		fmt.Println("Failed to uninstall cfdevd: ", err)
+	}
+	if err := os.Remove(program); err != nil {
+		fmt.Println("Failed to delete installed cfdevd:", err)
+	}
+	os.Remove(socketPath)
+}
+
+func listen() (*net.UnixListener, error) {
+	listeners, err := daemon.Listeners(SockName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to obtain socket from systemd: %s", err)
+	}
+	if len(listeners) == 1 {
+		listener, ok := listeners[0].(*net.UnixListener)
+		if !ok {
+			return nil, errors.New("Failed to cast listener to unix listener")
+		}
+		return listener, nil
+	}
+
+	os.Remove(socketPath)
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: socketPath, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to bind %s: %s", socketPath, err)
+	}
+	if err := os.Chmod(socketPath, 0666); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
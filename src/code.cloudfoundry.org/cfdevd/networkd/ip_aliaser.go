@@ -2,14 +2,10 @@ package networkd
 
 import (
 	"fmt"
-	"os/exec"
-	"os"
-	"strings"
 	"net"
+	"strings"
 )
 
-const loopback = "lo0"
-
 type HostNetD struct{}
 
 func (*HostNetD) AddLoopbackAliases(addrs ...string) error {
@@ -51,19 +47,6 @@ func (*HostNetD) RemoveLoopbackAliases(addrs ...string) error {
 	return nil
 }
 
-func addAlias(alias string) error {
-	cmd := exec.Command("sudo", "-S", "ifconfig", loopback, "add", alias+"/32")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
-}
-
-func createInterface() error {
-	return nil
-}
-
 func aliasExists(alias string) (bool, error) {
 	addrs, err := net.InterfaceAddrs()
 	if err != nil {
@@ -77,13 +60,3 @@ func aliasExists(alias string) (bool, error) {
 
 	return false, nil
 }
-
-func removeAlias(alias string) error {
-	cmd := exec.Command("sudo", "-S", "ifconfig", loopback, "inet", alias+"/32", "remove")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
-}
-
@@ -0,0 +1,30 @@
+package networkd
+
+import (
+	"os"
+	"os/exec"
+)
+
+const loopback = "lo0"
+
+func addAlias(alias string) error {
+	cmd := exec.Command("sudo", "-S", "ifconfig", loopback, "add", alias+"/32")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+func createInterface() error {
+	return nil
+}
+
+func removeAlias(alias string) error {
+	cmd := exec.Command("sudo", "-S", "ifconfig", loopback, "inet", alias+"/32", "remove")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
@@ -0,0 +1,44 @@
+package privileged
+
+import (
+	"net"
+
+	"code.cloudfoundry.org/cfdevd/networkd"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IP Aliaser - Linux", func() {
+	var hostnet *networkd.HostNetD
+
+	loAddrs := func() []string {
+		lo, err := net.InterfaceByName("lo")
+		Expect(err).NotTo(HaveOccurred())
+		addrs, err := lo.Addrs()
+		Expect(err).NotTo(HaveOccurred())
+
+		var ips []string
+		for _, addr := range addrs {
+			ips = append(ips, addr.(*net.IPNet).IP.String())
+		}
+		return ips
+	}
+
+	BeforeEach(func() {
+		hostnet = &networkd.HostNetD{}
+	})
+
+	AfterEach(func() {
+		Expect(hostnet.RemoveLoopbackAliases("123.123.123.123", "6.6.6.6")).To(Succeed())
+	})
+
+	It("adds and removes aliases on the lo interface", func() {
+		Expect(hostnet.AddLoopbackAliases("123.123.123.123", "6.6.6.6")).To(Succeed())
+		Expect(loAddrs()).To(ContainElement("123.123.123.123"))
+		Expect(loAddrs()).To(ContainElement("6.6.6.6"))
+
+		Expect(hostnet.RemoveLoopbackAliases("123.123.123.123", "6.6.6.6")).To(Succeed())
+		Expect(loAddrs()).NotTo(ContainElement("123.123.123.123"))
+		Expect(loAddrs()).NotTo(ContainElement("6.6.6.6"))
+	})
+})